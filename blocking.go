@@ -0,0 +1,211 @@
+package mapSet
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSetClosed是集合关闭且为空时PopWait和TryPop返回的错误
+var ErrSetClosed = errors.New("mapSet: set closed")
+
+// ErrSetCleared是等待期间集合被Clear时PopWait和TryPop返回的错误
+var ErrSetCleared = errors.New("mapSet: set cleared")
+
+// BlockingSet是可以用作去重工作队列的线程安全MapSet,
+// 消费者可以阻塞等待元素,而不需要反复调用Pop轮询。
+// Clone,Union等方法以及Filter,UnionAll等函数得到的新集合也是BlockingSet,但没有等待者且未关闭
+type BlockingSet interface {
+	MapSet
+
+	// 弹出一个元素,集合为空时阻塞直到有元素加入。
+	// ctx被取消时返回ctx.Err(),等待期间集合被Clear时返回ErrSetCleared,集合已关闭且为空时返回ErrSetClosed
+	PopWait(ctx context.Context) (interface{}, error)
+
+	// 与PopWait相同,但最多等待timeout,超时返回context.DeadlineExceeded
+	TryPop(timeout time.Duration) (interface{}, error)
+
+	// 关闭集合并以ErrSetClosed释放所有等待者。关闭后仍然可以增删元素,
+	// PopWait和TryPop会继续弹出剩余的元素,集合为空时立即返回ErrSetClosed
+	Close()
+}
+
+// blockingSet在threadSafeSet的基础上维护等待元素的PopWait调用。
+// 每加入一个元素唤醒一个等待者,被唤醒的等待者重新加锁取元素,元素已被取走时重新排队等待
+type blockingSet struct {
+	threadSafeSet
+	waiters list.List // 元素为chan error,唤醒时发送nil,释放时发送错误,由threadSafeSet的锁保护
+	closed  bool
+}
+
+// NewBlockingSet创建并返回一个可以阻塞等待元素的BlockingSet
+func NewBlockingSet(s ...interface{}) BlockingSet {
+	set := &blockingSet{threadSafeSet: newThreadSafeSet()}
+	for _, value := range s {
+		set.s.Add(value)
+	}
+	return set
+}
+
+// wrapBlocking把threadSafeSet运算得到的新集合包装为BlockingSet,新集合没有等待者且未关闭
+func wrapBlocking(s MapSet) MapSet {
+	return &blockingSet{threadSafeSet: threadSafeSet{s: s.(*threadSafeSet).s}}
+}
+
+// wakeLocked按等待顺序唤醒最多n个等待者,调用者需要持有写锁
+func (set *blockingSet) wakeLocked(n int) {
+	for ; n > 0 && set.waiters.Len() > 0; n-- {
+		ch := set.waiters.Remove(set.waiters.Front()).(chan error)
+		ch <- nil
+	}
+}
+
+// releaseLocked以err释放所有等待者,调用者需要持有写锁
+func (set *blockingSet) releaseLocked(err error) {
+	for set.waiters.Len() > 0 {
+		ch := set.waiters.Remove(set.waiters.Front()).(chan error)
+		ch <- err
+	}
+}
+
+func (set *blockingSet) PopWait(ctx context.Context) (interface{}, error) {
+	set.Lock()
+	for {
+		if set.s.RetElementCount() > 0 {
+			item := set.s.Pop()
+			set.Unlock()
+			return item, nil
+		}
+		if set.closed {
+			set.Unlock()
+			return nil, ErrSetClosed
+		}
+		if err := ctx.Err(); err != nil {
+			set.Unlock()
+			return nil, err
+		}
+
+		ch := make(chan error, 1)
+		e := set.waiters.PushBack(ch)
+		set.Unlock()
+
+		select {
+		case err := <-ch:
+			if err != nil {
+				return nil, err
+			}
+			set.Lock()
+		case <-ctx.Done():
+			set.Lock()
+			select {
+			case err := <-ch:
+				// 取消的同时已被唤醒,把唤醒转交给下一个等待者,避免元素无人处理
+				if err == nil && set.s.RetElementCount() > 0 {
+					set.wakeLocked(1)
+				}
+			default:
+				set.waiters.Remove(e)
+			}
+			set.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (set *blockingSet) TryPop(timeout time.Duration) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return set.PopWait(ctx)
+}
+
+func (set *blockingSet) Close() {
+	set.Lock()
+	set.closed = true
+	set.releaseLocked(ErrSetClosed)
+	set.Unlock()
+}
+
+// 以下方法在加入元素后按新加入的元素个数唤醒等待者
+
+// Clone及以下返回新集合的运算的结果也是BlockingSet
+
+func (set *blockingSet) Clone() MapSet {
+	return wrapBlocking(set.threadSafeSet.Clone())
+}
+
+func (set *blockingSet) Union(other ReadOnlySet) MapSet {
+	return wrapBlocking(set.threadSafeSet.Union(other))
+}
+
+func (set *blockingSet) Intersect(other ReadOnlySet) MapSet {
+	return wrapBlocking(set.threadSafeSet.Intersect(other))
+}
+
+func (set *blockingSet) Difference(other ReadOnlySet) MapSet {
+	return wrapBlocking(set.threadSafeSet.Difference(other))
+}
+
+func (set *blockingSet) SymmetricDifference(other ReadOnlySet) MapSet {
+	return wrapBlocking(set.threadSafeSet.SymmetricDifference(other))
+}
+
+func (set *blockingSet) Add(i interface{}) bool {
+	set.Lock()
+	ret := set.s.Add(i)
+	if ret {
+		set.wakeLocked(1)
+	}
+	set.Unlock()
+	return ret
+}
+
+func (set *blockingSet) AddAll(items ...interface{}) int {
+	set.Lock()
+	ret := set.s.AddAll(items...)
+	set.wakeLocked(ret)
+	set.Unlock()
+	return ret
+}
+
+func (set *blockingSet) UnionWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.UnionWith(o)
+	set.wakeLocked(ret)
+	unlock()
+	return ret
+}
+
+func (set *blockingSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	before := set.s.RetElementCount()
+	ret := set.s.SymmetricDifferenceWith(o)
+	set.wakeLocked(set.s.RetElementCount() - before)
+	unlock()
+	return ret
+}
+
+func (set *blockingSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	set.Lock()
+	defer set.Unlock()
+	before := set.s.RetElementCount()
+	set.s.EachMutable(cb)
+	set.wakeLocked(set.s.RetElementCount() - before)
+}
+
+// Clear清空集合,并以ErrSetCleared释放所有等待者
+func (set *blockingSet) Clear() {
+	set.Lock()
+	set.s.Clear()
+	set.releaseLocked(ErrSetCleared)
+	set.Unlock()
+}
+
+func (set *blockingSet) UnmarshalJSON(b []byte) error {
+	decoded := newThreadUnsafeSet()
+	if err := decoded.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	set.AddAll(decoded.ToSlice()...)
+	return nil
+}
@@ -0,0 +1,213 @@
+package mapSet
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitersOf返回s中正在等待的PopWait个数
+func waitersOf(s BlockingSet) int {
+	b := s.(*blockingSet)
+	b.RLock()
+	defer b.RUnlock()
+	return b.waiters.Len()
+}
+
+// awaitWaiters轮询直到s中至少有n个PopWait在等待,不用固定的Sleep猜测等待者何时就绪
+func awaitWaiters(s BlockingSet, n int) {
+	for waitersOf(s) < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_PopWait(t *testing.T) {
+	s := NewBlockingSet(1)
+	if item, err := s.PopWait(context.Background()); err != nil || item != 1 {
+		t.Errorf("expected to pop 1 immediately, got %v, %v", item, err)
+	}
+
+	done := make(chan interface{})
+	go func() {
+		item, _ := s.PopWait(context.Background())
+		done <- item
+	}()
+	awaitWaiters(s, 1)
+	s.Add(2)
+
+	select {
+	case item := <-done:
+		if item != 2 || s.RetElementCount() != 0 {
+			t.Errorf("expected the waiter to pop 2, got %v", item)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Add should wake the waiter")
+	}
+}
+
+func Test_PopWaitCancel(t *testing.T) {
+	s := NewBlockingSet()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.PopWait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	start := time.Now()
+	if _, err := s.TryPop(10 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Error("TryPop should wait for the timeout")
+	}
+
+	// 超时的等待者不应留在等待队列中
+	s.Add(1)
+	if item, err := s.TryPop(time.Second); err != nil || item != 1 {
+		t.Errorf("expected to pop 1, got %v, %v", item, err)
+	}
+}
+
+func Test_PopWaitWakesOne(t *testing.T) {
+	s := NewBlockingSet()
+
+	var popped int64
+	var wg sync.WaitGroup
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := s.TryPop(200 * time.Millisecond); err == nil {
+				atomic.AddInt64(&popped, 1)
+			}
+		}()
+	}
+	awaitWaiters(s, 3)
+
+	s.Add(1)
+	if n := waitersOf(s); n != 2 {
+		t.Errorf("Add should wake exactly one waiter, %d still waiting", n)
+	}
+	wg.Wait()
+	if popped != 1 {
+		t.Errorf("one element should be popped by exactly one waiter, popped %d", popped)
+	}
+}
+
+func Test_PopWaitReleased(t *testing.T) {
+	for _, tc := range []struct {
+		release func(BlockingSet)
+		err     error
+	}{
+		{func(s BlockingSet) { s.Clear() }, ErrSetCleared},
+		{func(s BlockingSet) { s.Close() }, ErrSetClosed},
+	} {
+		s := NewBlockingSet()
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				_, err := s.PopWait(context.Background())
+				errs <- err
+			}()
+		}
+		awaitWaiters(s, 2)
+		tc.release(s)
+
+		for i := 0; i < 2; i++ {
+			select {
+			case err := <-errs:
+				if err != tc.err {
+					t.Errorf("expected %v, got %v", tc.err, err)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("waiters should be released with %v", tc.err)
+			}
+		}
+	}
+
+	s := NewBlockingSet(1)
+	s.Close()
+	if item, err := s.PopWait(context.Background()); err != nil || item != 1 {
+		t.Errorf("a closed set should still pop its remaining elements, got %v, %v", item, err)
+	}
+	if _, err := s.PopWait(context.Background()); err != ErrSetClosed {
+		t.Errorf("expected ErrSetClosed, got %v", err)
+	}
+}
+
+func Test_PopWaitConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewBlockingSet()
+	results := make(chan interface{}, N)
+	var wg sync.WaitGroup
+	wg.Add(8)
+	for i := 0; i < 8; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				item, err := s.PopWait(context.Background())
+				if err != nil {
+					return
+				}
+				results <- item
+			}
+		}()
+	}
+
+	for i := 0; i < N; i++ {
+		if i%2 == 0 {
+			s.Add(i)
+		} else {
+			s.AddAll(i)
+		}
+	}
+	seen := NewThreadUnsafeSet()
+	for i := 0; i < N; i++ {
+		select {
+		case item := <-results:
+			seen.Add(item)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d of %d elements were popped", i, N)
+		}
+	}
+	s.Close()
+	wg.Wait()
+
+	if seen.RetElementCount() != N {
+		t.Errorf("expected %d distinct elements, got %d", N, seen.RetElementCount())
+	}
+}
+
+func Test_BlockingSetDerivedSets(t *testing.T) {
+	s := NewBlockingSet(1, 2, 3)
+	s.Close()
+	derived := []MapSet{
+		s.Clone(),
+		s.Union(NewMapSet(4)),
+		s.Intersect(NewMapSet(1)),
+		s.Difference(NewMapSet(1)),
+		s.SymmetricDifference(NewMapSet(4)),
+		Filter(s, isEven),
+		UnionAll(s, NewMapSet(4)),
+		IntersectAll(s, NewMapSet(1, 2)),
+	}
+	for i, d := range derived {
+		b, ok := d.(BlockingSet)
+		if !ok {
+			t.Fatalf("derived set %d should be a BlockingSet, got %T", i, d)
+		}
+		// 新集合未关闭,清空后PopWait会等待而不是返回ErrSetClosed
+		b.Clear()
+		if _, err := b.TryPop(time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("derived set %d should not be closed, got %v", i, err)
+		}
+	}
+	if s.RetElementCount() != 3 {
+		t.Errorf("deriving sets should not modify the original, got %v", s.ToSlice())
+	}
+}
@@ -0,0 +1,332 @@
+package mapSet
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// copyOnWriteSet是写时复制的线程安全实现,适合读远多于写的场景。
+// m指向的快照发布后不再修改,读操作直接读取当前快照,不加任何锁;
+// 写操作在mu下复制当前快照,修改副本后再原子地替换。每次写操作的代价为O(n)
+type copyOnWriteSet struct {
+	m  atomic.Pointer[threadUnsafeSet]
+	mu sync.Mutex
+}
+
+// NewCopyOnWriteSet创建写时复制的MapSet。读操作无锁,写操作复制整个集合,
+// 适合读多写少的场景,例如频繁查询,偶尔更新的白名单。
+// Each等遍历方法遍历调用时的快照,回调中可以修改集合
+func NewCopyOnWriteSet(s ...interface{}) MapSet {
+	set := newThreadUnsafeSet()
+	for _, value := range s {
+		set.Add(value)
+	}
+	return newCopyOnWriteSet(&set)
+}
+
+func newCopyOnWriteSet(s *threadUnsafeSet) *copyOnWriteSet {
+	set := &copyOnWriteSet{}
+	set.m.Store(s)
+	return set
+}
+
+// wrapCopyOnWrite把非线程安全实现的运算结果包装为copyOnWriteSet,结果不再被其他人修改
+func wrapCopyOnWrite(s MapSet) MapSet {
+	return newCopyOnWriteSet(s.(*threadUnsafeSet))
+}
+
+// load返回当前快照,快照只能读取
+func (set *copyOnWriteSet) load() *threadUnsafeSet {
+	return set.m.Load()
+}
+
+// update在写锁下复制当前快照并用fn修改副本,fn返回true时发布副本
+func (set *copyOnWriteSet) update(fn func(next *threadUnsafeSet) bool) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	next := set.load().Clone().(*threadUnsafeSet)
+	if fn(next) {
+		set.m.Store(next)
+	}
+}
+
+// snapshotOf在other是copyOnWriteSet时返回它的当前快照,使一次运算只看到other的一个版本
+func snapshotOf(other ReadOnlySet) ReadOnlySet {
+	other = orEmpty(other)
+	if o, ok := other.(*copyOnWriteSet); ok && o != nil {
+		return o.load()
+	}
+	return other
+}
+
+func (set *copyOnWriteSet) Add(i interface{}) bool {
+	if set.load().Contains(i) {
+		return false
+	}
+	ret := false
+	set.update(func(next *threadUnsafeSet) bool {
+		ret = next.Add(i)
+		return ret
+	})
+	return ret
+}
+
+func (set *copyOnWriteSet) Contains(i ...interface{}) bool {
+	return set.load().Contains(i...)
+}
+
+func (set *copyOnWriteSet) Clear() {
+	set.mu.Lock()
+	empty := threadUnsafeSet{m: make(map[interface{}]int), rnd: set.load().rnd}
+	set.m.Store(&empty)
+	set.mu.Unlock()
+}
+
+func (set *copyOnWriteSet) setRandSource(r *randSource) {
+	set.update(func(next *threadUnsafeSet) bool {
+		next.rnd = r
+		return true
+	})
+}
+
+func (set *copyOnWriteSet) Remove(i interface{}) {
+	set.Delete(i)
+}
+
+func (set *copyOnWriteSet) Delete(i interface{}) bool {
+	if !set.load().Contains(i) {
+		return false
+	}
+	ret := false
+	set.update(func(next *threadUnsafeSet) bool {
+		ret = next.Delete(i)
+		return ret
+	})
+	return ret
+}
+
+func (set *copyOnWriteSet) AddAll(items ...interface{}) int {
+	n := 0
+	set.update(func(next *threadUnsafeSet) bool {
+		n = next.AddAll(items...)
+		return n > 0
+	})
+	return n
+}
+
+func (set *copyOnWriteSet) RemoveAll(items ...interface{}) int {
+	if !set.load().ContainsAny(items...) {
+		return 0
+	}
+	n := 0
+	set.update(func(next *threadUnsafeSet) bool {
+		n = next.RemoveAll(items...)
+		return n > 0
+	})
+	return n
+}
+
+func (set *copyOnWriteSet) ContainsAny(items ...interface{}) bool {
+	return set.load().ContainsAny(items...)
+}
+
+func (set *copyOnWriteSet) RetElementCount() int {
+	return set.load().RetElementCount()
+}
+
+func (set *copyOnWriteSet) Each(cb func(interface{}) bool) {
+	set.load().Each(cb)
+}
+
+func (set *copyOnWriteSet) RemoveIf(pred func(interface{}) bool) int {
+	n := 0
+	set.update(func(next *threadUnsafeSet) bool {
+		n = next.RemoveIf(pred)
+		return n > 0
+	})
+	return n
+}
+
+func (set *copyOnWriteSet) RetainIf(pred func(interface{}) bool) int {
+	n := 0
+	set.update(func(next *threadUnsafeSet) bool {
+		n = next.RetainIf(pred)
+		return n > 0
+	})
+	return n
+}
+
+func (set *copyOnWriteSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	set.update(func(next *threadUnsafeSet) bool {
+		next.EachMutable(cb)
+		return true
+	})
+}
+
+func (set *copyOnWriteSet) Pop() interface{} {
+	if set.load().RetElementCount() == 0 {
+		return nil
+	}
+	var ret interface{}
+	set.update(func(next *threadUnsafeSet) bool {
+		if next.RetElementCount() == 0 {
+			return false
+		}
+		ret = next.Pop()
+		return true
+	})
+	return ret
+}
+
+func (set *copyOnWriteSet) Equal(other ReadOnlySet) bool {
+	return set.load().Equal(snapshotOf(other))
+}
+
+func (set *copyOnWriteSet) Union(other ReadOnlySet) MapSet {
+	return wrapCopyOnWrite(set.load().Union(snapshotOf(other)))
+}
+
+func (set *copyOnWriteSet) Intersect(other ReadOnlySet) MapSet {
+	return wrapCopyOnWrite(set.load().Intersect(snapshotOf(other)))
+}
+
+func (set *copyOnWriteSet) Difference(other ReadOnlySet) MapSet {
+	return wrapCopyOnWrite(set.load().Difference(snapshotOf(other)))
+}
+
+func (set *copyOnWriteSet) SymmetricDifference(other ReadOnlySet) MapSet {
+	return wrapCopyOnWrite(set.load().SymmetricDifference(snapshotOf(other)))
+}
+
+// 以下原地运算在写锁内读取other,读取copyOnWriteSet不加锁,因此other是调用者自身时也不会死锁
+
+func (set *copyOnWriteSet) UnionWith(other ReadOnlySet) int {
+	n := 0
+	set.update(func(next *threadUnsafeSet) bool {
+		n = next.UnionWith(snapshotOf(other))
+		return n > 0
+	})
+	return n
+}
+
+func (set *copyOnWriteSet) IntersectWith(other ReadOnlySet) int {
+	n := 0
+	set.update(func(next *threadUnsafeSet) bool {
+		n = next.IntersectWith(snapshotOf(other))
+		return n > 0
+	})
+	return n
+}
+
+func (set *copyOnWriteSet) DifferenceWith(other ReadOnlySet) int {
+	n := 0
+	set.update(func(next *threadUnsafeSet) bool {
+		n = next.DifferenceWith(snapshotOf(other))
+		return n > 0
+	})
+	return n
+}
+
+func (set *copyOnWriteSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	n := 0
+	set.update(func(next *threadUnsafeSet) bool {
+		n = next.SymmetricDifferenceWith(snapshotOf(other))
+		return n > 0
+	})
+	return n
+}
+
+func (set *copyOnWriteSet) IsSubset(other ReadOnlySet) bool {
+	return set.load().IsSubset(snapshotOf(other))
+}
+
+func (set *copyOnWriteSet) IsProperSubset(other ReadOnlySet) bool {
+	return set.load().IsProperSubset(snapshotOf(other))
+}
+
+func (set *copyOnWriteSet) IsSuperset(other ReadOnlySet) bool {
+	return set.load().IsSuperset(snapshotOf(other))
+}
+
+func (set *copyOnWriteSet) IsProperSuperset(other ReadOnlySet) bool {
+	return set.load().IsProperSuperset(snapshotOf(other))
+}
+
+func (set *copyOnWriteSet) IsDisjoint(other ReadOnlySet) bool {
+	return set.load().IsDisjoint(snapshotOf(other))
+}
+
+// Clone与原集合共享当前快照,不复制元素,两者之后的写操作各自复制
+func (set *copyOnWriteSet) Clone() MapSet {
+	return newCopyOnWriteSet(set.load())
+}
+
+func (set *copyOnWriteSet) String(sep string) string {
+	return set.load().String(sep)
+}
+
+func (set *copyOnWriteSet) RandomReturn() interface{} {
+	return set.load().RandomReturn()
+}
+
+func (set *copyOnWriteSet) Sample(k int) []interface{} {
+	return set.load().Sample(k)
+}
+
+func (set *copyOnWriteSet) SampleWithReplacement(k int) []interface{} {
+	return set.load().SampleWithReplacement(k)
+}
+
+func (set *copyOnWriteSet) PopN(k int) []interface{} {
+	if k <= 0 || set.load().RetElementCount() == 0 {
+		return []interface{}{}
+	}
+	var ret []interface{}
+	set.update(func(next *threadUnsafeSet) bool {
+		ret = next.PopN(k)
+		return len(ret) > 0
+	})
+	return ret
+}
+
+func (set *copyOnWriteSet) Shuffled() []interface{} {
+	return set.load().Shuffled()
+}
+
+func (set *copyOnWriteSet) ToSlice() []interface{} {
+	return set.load().ToSlice()
+}
+
+func (set *copyOnWriteSet) Freeze() FrozenSet {
+	return set.load().Freeze()
+}
+
+func (set *copyOnWriteSet) Iterator() *Iterator {
+	return newIterator(set.ToSlice())
+}
+
+// All每次range开始时取得当前快照并直接遍历,不需要复制元素
+func (set *copyOnWriteSet) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for elem := range set.load().m {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+func (set *copyOnWriteSet) MarshalJSON() ([]byte, error) {
+	return set.load().MarshalJSON()
+}
+
+// UnmarshalJSON先在锁外解码JSON数组,再一次性把元素加入集合
+func (set *copyOnWriteSet) UnmarshalJSON(b []byte) error {
+	decoded := newThreadUnsafeSet()
+	if err := decoded.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	set.AddAll(decoded.ToSlice()...)
+	return nil
+}
@@ -0,0 +1,114 @@
+package mapSet
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func Test_CopyOnWriteSnapshot(t *testing.T) {
+	s := NewCopyOnWriteSet(1, 2, 3)
+	c := s.Clone()
+
+	// 遍历的是调用时的快照,回调中可以修改集合
+	n := 0
+	s.Each(func(elem interface{}) bool {
+		s.Add(elem.(int) + 10)
+		n++
+		return false
+	})
+	if n != 3 || s.RetElementCount() != 6 {
+		t.Errorf("expected to visit 3 elements and end with 6, visited %d, got %v", n, s.ToSlice())
+	}
+
+	if c.RetElementCount() != 3 || c.Contains(11) {
+		t.Errorf("modifying the set should not affect its clone, got %v", c.ToSlice())
+	}
+	c.Remove(1)
+	if !s.Contains(1) {
+		t.Error("modifying the clone should not affect the set")
+	}
+
+	if s.UnionWith(s) != 0 || s.SymmetricDifferenceWith(s) != 6 || s.RetElementCount() != 0 {
+		t.Errorf("in-place operations with itself should not deadlock, got %v", s.ToSlice())
+	}
+}
+
+func Test_CopyOnWriteConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewCopyOnWriteSet()
+	var wg sync.WaitGroup
+	wg.Add(2 * N)
+	for i := 0; i < N; i++ {
+		go func(i int) {
+			s.Add(i)
+			wg.Done()
+		}(i)
+		go func(i int) {
+			s.Contains(i)
+			s.RetElementCount()
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	if s.RetElementCount() != N {
+		t.Errorf("expected %d elements, got %d", N, s.RetElementCount())
+	}
+	for i := 0; i < N; i++ {
+		if !s.Contains(i) {
+			t.Errorf("Set is missing element: %v", i)
+		}
+	}
+}
+
+func benchmarkContains(b *testing.B, s MapSet) {
+	for i := 0; i < N; i++ {
+		s.Add(i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			s.Contains(i % N)
+			i++
+		}
+	})
+}
+
+func Benchmark_ContainsThreadSafe(b *testing.B) {
+	benchmarkContains(b, NewMapSet())
+}
+
+func Benchmark_ContainsCopyOnWrite(b *testing.B) {
+	benchmarkContains(b, NewCopyOnWriteSet())
+}
+
+// 每1000次操作中有一次删除并重新加入元素,其余为读操作
+func benchmarkReadMostly(b *testing.B, s MapSet) {
+	for i := 0; i < N; i++ {
+		s.Add(i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%1000 == 0 {
+				s.Remove(i % N)
+				s.Add(i % N)
+			} else {
+				s.Contains(i % N)
+			}
+			i++
+		}
+	})
+}
+
+func Benchmark_ReadMostlyThreadSafe(b *testing.B) {
+	benchmarkReadMostly(b, NewMapSet())
+}
+
+func Benchmark_ReadMostlyCopyOnWrite(b *testing.B) {
+	benchmarkReadMostly(b, NewCopyOnWriteSet())
+}
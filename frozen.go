@@ -0,0 +1,88 @@
+package mapSet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FrozenSet是MapSet的不可变快照,由MapSet.Freeze创建。
+// FrozenSet是可比较的值类型:元素相同的两个FrozenSet用==比较相等,
+// 因此可以作为map的键,也可以作为另一个MapSet的元素,用来表示集合的集合。
+// 它的标识是与元素顺序无关的内容哈希加元素个数,元素按与==一致的规范顺序保存,
+// 因此指针元素按地址区分,0.0与-0.0视为同一个元素。
+// Contains需要O(n)时间,需要频繁查询时请先用ToMapSet转换
+type FrozenSet struct {
+	hash  uint64
+	n     int
+	elems Tuple
+}
+
+func newFrozenSet(elems []interface{}) FrozenSet {
+	var sum uint64
+	for _, elem := range elems {
+		// 对元素哈希求和,结果与元素顺序无关
+		sum += mix64(hashElem(elem))
+	}
+	return FrozenSet{
+		hash:  mix64(sum ^ uint64(len(elems))),
+		n:     len(elems),
+		elems: NewTuple(canonicalOrder(elems)...),
+	}
+}
+
+// NewFrozenSet用给定元素创建FrozenSet,重复的元素只保留一个
+func NewFrozenSet(elems ...interface{}) FrozenSet {
+	return newFrozenSet(NewThreadUnsafeSetFromSlice(elems).ToSlice())
+}
+
+// Hash返回与元素顺序无关的内容哈希,元素相同的FrozenSet哈希相同。
+// 哈希的种子在进程启动时随机生成,不要在进程之间传递或持久化哈希值
+func (f FrozenSet) Hash() uint64 {
+	return f.hash
+}
+
+// Len返回元素个数
+func (f FrozenSet) Len() int {
+	return f.n
+}
+
+// Contains给定一系列元素，判断这些元素是否都在FrozenSet中
+func (f FrozenSet) Contains(i ...interface{}) bool {
+	elems := f.ToSlice()
+	for _, val := range i {
+		found := false
+		for _, elem := range elems {
+			if elem == val {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal判断两个FrozenSet是否相等,等价于f == other
+func (f FrozenSet) Equal(other FrozenSet) bool {
+	return f == other
+}
+
+// ToSlice按规范顺序把元素作为切片返回
+func (f FrozenSet) ToSlice() []interface{} {
+	return f.elems.ToSlice()
+}
+
+// ToMapSet把FrozenSet转换为一个新的线程不安全的MapSet
+func (f FrozenSet) ToMapSet() MapSet {
+	return NewThreadUnsafeSetFromSlice(f.ToSlice())
+}
+
+func (f FrozenSet) String() string {
+	items := make([]string, 0, f.n)
+	for _, elem := range f.ToSlice() {
+		items = append(items, fmt.Sprintf("%v", elem))
+	}
+	return fmt.Sprintf("FrozenSet{%s}", strings.Join(items, ", "))
+}
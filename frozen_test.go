@@ -0,0 +1,101 @@
+package mapSet
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_Freeze(t *testing.T) {
+	var frozen []FrozenSet
+	for _, s := range allSetKinds(3, 1, 2) {
+		f := s.Freeze()
+		if f.Len() != 3 || !f.Contains(1, 2, 3) || f.Contains(4) {
+			t.Errorf("%T: unexpected frozen set %v", s, f)
+		}
+
+		s.Add(4)
+		if f.Contains(4) {
+			t.Errorf("%T: modifying the set should not affect the frozen set", s)
+		}
+		frozen = append(frozen, f)
+	}
+
+	for _, f := range frozen[1:] {
+		if f != frozen[0] || f.Hash() != frozen[0].Hash() || !f.Equal(frozen[0]) {
+			t.Errorf("frozen sets with the same elements should be equal: %v != %v", f, frozen[0])
+		}
+	}
+}
+
+func Test_FreezeSortedSetWithUncomparable(t *testing.T) {
+	byLen := func(a, b interface{}) int {
+		return len(a.(string)) - len(b.(string))
+	}
+	for _, s := range []SortedSet{NewSortedSet(byLen, "a", "bb"), NewThreadUnsafeSortedSet(byLen, "a", "bb")} {
+		// 不可比较的元素在Add时被拒绝,不会进入集合,因此Freeze不会因计算哈希而panic
+		s.Add([]int{1, 2, 3})
+		s.Add(struct{ V interface{} }{map[string]int{}})
+		if f := s.Freeze(); f != NewFrozenSet("a", "bb") {
+			t.Errorf("%T: unexpected frozen set %v", s, f)
+		}
+	}
+}
+
+func Test_FrozenSetIdentity(t *testing.T) {
+	a := NewFrozenSet("read", "write")
+	b := NewMapSet("write", "read").Freeze()
+	c := NewFrozenSet("read")
+
+	if a != b || a.Hash() != b.Hash() {
+		t.Error("equal frozen sets should be equal and have the same hash")
+	}
+	if a == c || a.Hash() == c.Hash() {
+		t.Error("different frozen sets should not be equal")
+	}
+	if NewFrozenSet() != NewThreadUnsafeSet().Freeze() {
+		t.Error("empty frozen sets should be equal")
+	}
+
+	// 相同的权限组作为map的键会合并
+	groups := map[FrozenSet]int{}
+	groups[a]++
+	groups[b]++
+	groups[c]++
+	if len(groups) != 2 || groups[a] != 2 {
+		t.Errorf("unexpected groups %v", groups)
+	}
+}
+
+func Test_FrozenSetEqualElements(t *testing.T) {
+	x, y := 1, 1
+	p1, p2 := &x, &y
+	if NewFrozenSet(p1, p2) != NewFrozenSet(p2, p1) {
+		t.Error("frozen sets of the same pointers should be equal regardless of order")
+	}
+
+	before := NewMapSet(p1, p2).Freeze()
+	x = 5
+	if after := NewMapSet(p2, p1).Freeze(); before != after || before.Hash() != after.Hash() {
+		t.Error("pointers are compared by address, so changing a pointee should not change the frozen set")
+	}
+
+	zero, negZero := NewFrozenSet(0.0), NewFrozenSet(math.Copysign(0, -1))
+	if zero != negZero || zero.Hash() != negZero.Hash() {
+		t.Error("{0.0} and {-0.0} are equal sets and should freeze equal")
+	}
+}
+
+func Test_SetOfFrozenSets(t *testing.T) {
+	s := NewMapSet()
+	s.Add(NewMapSet(1, 2).Freeze())
+	s.Add(NewThreadUnsafeSetFromSlice([]interface{}{2, 1}).Freeze())
+	s.Add(NewMapSet(3).Freeze())
+
+	if s.RetElementCount() != 2 {
+		t.Errorf("a set of frozen sets should de-duplicate equal sets, got %v", s.ToSlice())
+	}
+	if !s.Contains(NewFrozenSet(1, 2)) {
+		t.Error("the set should contain {1, 2}")
+	}
+	assertEqual(NewFrozenSet(1, 2).ToMapSet(), makeSet([]int{1, 2}), t)
+}
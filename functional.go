@@ -0,0 +1,195 @@
+package mapSet
+
+// 函数式操作对所有实现只实现一次。elemsOf负责遍历元素:不带锁的实现直接使用自身的Each,
+// 其他实现使用ToSlice在锁内取得的快照,这样回调在锁外执行,回调中也可以访问原集合。
+// 需要创建新集合的操作用emptyLike得到与s同一种实现的空集合,nil视为空集
+
+// Filter返回s中满足pred的元素组成的新MapSet,结果与s是同一种实现
+func Filter(s MapSet, pred func(interface{}) bool) MapSet {
+	return filterEach(elemsOf(s), pred, emptyLike(s))
+}
+
+// Map返回对s的每个元素调用fn的结果组成的新MapSet,重复的结果只保留一个。
+// 结果与s的线程安全性相同,元素类型可能改变,因此有序的实现也返回普通MapSet
+func Map(s MapSet, fn func(interface{}) interface{}) MapSet {
+	return mapEach(elemsOf(s), fn, plainLike(s))
+}
+
+// Reduce从init开始依次用fn合并s的每个元素,返回最终结果
+func Reduce(s ReadOnlySet, init interface{}, fn func(acc, elem interface{}) interface{}) interface{} {
+	return reduceEach(elemsOf(s), init, fn)
+}
+
+// Any判断s中是否存在满足pred的元素
+func Any(s ReadOnlySet, pred func(interface{}) bool) bool {
+	_, found := findEach(elemsOf(s), pred)
+	return found
+}
+
+// Every判断s中是否所有元素都满足pred,空集返回true
+func Every(s ReadOnlySet, pred func(interface{}) bool) bool {
+	_, found := findEach(elemsOf(s), func(elem interface{}) bool {
+		return !pred(elem)
+	})
+	return !found
+}
+
+// Find返回s中一个满足pred的元素,不存在时第二个返回值为false
+func Find(s ReadOnlySet, pred func(interface{}) bool) (interface{}, bool) {
+	return findEach(elemsOf(s), pred)
+}
+
+// Count返回s中满足pred的元素个数
+func Count(s ReadOnlySet, pred func(interface{}) bool) int {
+	return countEach(elemsOf(s), pred)
+}
+
+// Partition把s的元素按是否满足pred分为两个新MapSet,结果与s是同一种实现
+func Partition(s MapSet, pred func(interface{}) bool) (in, out MapSet) {
+	return partitionEach(elemsOf(s), pred, emptyLike(s), emptyLike(s))
+}
+
+// GroupBy按keyFn的结果把s的元素分组,每组是与s同一种实现的新MapSet
+func GroupBy(s MapSet, keyFn func(interface{}) interface{}) map[interface{}]MapSet {
+	return groupEach(elemsOf(s), keyFn, func() MapSet {
+		return emptyLike(s)
+	})
+}
+
+// IndexBy按keysFn返回的每个键建立索引,一个元素会出现在它所有键对应的集合中,
+// 每个集合是与s同一种实现的新MapSet
+func IndexBy(s MapSet, keysFn func(interface{}) []interface{}) Index {
+	return indexEach(elemsOf(s), keysFn, func() MapSet {
+		return emptyLike(s)
+	})
+}
+
+// elemsOf返回遍历s的函数,不带锁的实现直接遍历,其他实现遍历ToSlice得到的快照
+func elemsOf(s ReadOnlySet) func(func(interface{}) bool) {
+	s = orEmpty(s)
+	if isThreadUnsafe(s) {
+		return s.Each
+	}
+	return sliceEach(s.ToSlice())
+}
+
+func sliceEach(items []interface{}) func(func(interface{}) bool) {
+	return func(cb func(interface{}) bool) {
+		for _, elem := range items {
+			if cb(elem) {
+				return
+			}
+		}
+	}
+}
+
+func filterEach(each func(func(interface{}) bool), pred func(interface{}) bool, dst MapSet) MapSet {
+	each(func(elem interface{}) bool {
+		if pred(elem) {
+			dst.Add(elem)
+		}
+		return false
+	})
+	return dst
+}
+
+func mapEach(each func(func(interface{}) bool), fn func(interface{}) interface{}, dst MapSet) MapSet {
+	each(func(elem interface{}) bool {
+		dst.Add(fn(elem))
+		return false
+	})
+	return dst
+}
+
+func reduceEach(each func(func(interface{}) bool), init interface{}, fn func(acc, elem interface{}) interface{}) interface{} {
+	acc := init
+	each(func(elem interface{}) bool {
+		acc = fn(acc, elem)
+		return false
+	})
+	return acc
+}
+
+func findEach(each func(func(interface{}) bool), pred func(interface{}) bool) (interface{}, bool) {
+	var found interface{}
+	ok := false
+	each(func(elem interface{}) bool {
+		if pred(elem) {
+			found, ok = elem, true
+			return true
+		}
+		return false
+	})
+	return found, ok
+}
+
+func countEach(each func(func(interface{}) bool), pred func(interface{}) bool) int {
+	count := 0
+	each(func(elem interface{}) bool {
+		if pred(elem) {
+			count++
+		}
+		return false
+	})
+	return count
+}
+
+func partitionEach(each func(func(interface{}) bool), pred func(interface{}) bool, in, out MapSet) (MapSet, MapSet) {
+	each(func(elem interface{}) bool {
+		if pred(elem) {
+			in.Add(elem)
+		} else {
+			out.Add(elem)
+		}
+		return false
+	})
+	return in, out
+}
+
+// Index是IndexBy的结果,键为索引键,值为对应的元素集合。一个元素可以出现在多个键下
+type Index map[interface{}]MapSet
+
+// Get返回key对应的集合,key不存在时返回nil
+func (idx Index) Get(key interface{}) MapSet {
+	return idx[key]
+}
+
+// Keys返回所有索引键
+func (idx Index) Keys() []interface{} {
+	keys := make([]interface{}, 0, len(idx))
+	for key := range idx {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func groupEach(each func(func(interface{}) bool), keyFn func(interface{}) interface{}, newBucket func() MapSet) map[interface{}]MapSet {
+	groups := make(map[interface{}]MapSet)
+	each(func(elem interface{}) bool {
+		key := keyFn(elem)
+		bucket, ok := groups[key]
+		if !ok {
+			bucket = newBucket()
+			groups[key] = bucket
+		}
+		bucket.Add(elem)
+		return false
+	})
+	return groups
+}
+
+func indexEach(each func(func(interface{}) bool), keysFn func(interface{}) []interface{}, newBucket func() MapSet) Index {
+	idx := make(Index)
+	each(func(elem interface{}) bool {
+		for _, key := range keysFn(elem) {
+			bucket, ok := idx[key]
+			if !ok {
+				bucket = newBucket()
+				idx[key] = bucket
+			}
+			bucket.Add(elem)
+		}
+		return false
+	})
+	return idx
+}
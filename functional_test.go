@@ -0,0 +1,168 @@
+package mapSet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func isEven(elem interface{}) bool {
+	return elem.(int)%2 == 0
+}
+
+func Test_Filter(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4, 5, 6) {
+		evens := Filter(s, isEven)
+		assertEqual(evens, makeSet([]int{2, 4, 6}), t)
+		if reflect.TypeOf(evens) != reflect.TypeOf(s) {
+			t.Errorf("Filter on %T returned %T", s, evens)
+		}
+		if s.RetElementCount() != 6 {
+			t.Errorf("%T: Filter should not modify the set", s)
+		}
+	}
+
+	filtered := Filter(NewOrderedSet(LIFO, 5, 4, 3, 2), isEven)
+	assertSliceEqual(filtered.ToSlice(), []interface{}{4, 2}, t)
+	if filtered.Pop() != 2 {
+		t.Error("Filter should keep the pop order of an ordered set")
+	}
+}
+
+func Test_Map(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		halves := Map(s, func(elem interface{}) interface{} {
+			return elem.(int) / 2
+		})
+		assertEqual(halves, makeSet([]int{0, 1, 2}), t)
+	}
+
+	if _, ok := Map(makeSet([]int{1}), func(e interface{}) interface{} { return e }).(*threadSafeSet); !ok {
+		t.Error("Map should keep the thread-safety of the receiver")
+	}
+	if _, ok := Map(NewThreadUnsafeSortedSet(OrderedComparator[int](), 1), func(e interface{}) interface{} { return e }).(*threadUnsafeSet); !ok {
+		t.Error("Map on an unsafe sorted set should return an unsafe set")
+	}
+}
+
+func Test_Reduce(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		sum := Reduce(s, 0, func(acc, elem interface{}) interface{} {
+			return acc.(int) + elem.(int)
+		})
+		if sum != 10 {
+			t.Errorf("%T: the sum should be 10, got %v", s, sum)
+		}
+	}
+}
+
+func Test_AnyEvery(t *testing.T) {
+	for _, s := range allSetKinds(2, 4, 6) {
+		if !Any(s, isEven) || !Every(s, isEven) {
+			t.Errorf("%T: every element is even", s)
+		}
+		s.Add(7)
+		if !Any(s, isEven) || Every(s, isEven) {
+			t.Errorf("%T: 7 is not even", s)
+		}
+	}
+
+	for _, s := range allSetKinds() {
+		if Any(s, isEven) || !Every(s, isEven) {
+			t.Errorf("%T: Any should be false and Every should be true on an empty set", s)
+		}
+	}
+}
+
+func Test_FindCount(t *testing.T) {
+	for _, s := range allSetKinds(1, 3, 4, 5) {
+		if v, ok := Find(s, isEven); !ok || v != 4 {
+			t.Errorf("%T: Find should return 4, got %v", s, v)
+		}
+		if _, ok := Find(s, func(elem interface{}) bool { return elem.(int) > 10 }); ok {
+			t.Errorf("%T: Find should not find anything greater than 10", s)
+		}
+		if Count(s, isEven) != 1 {
+			t.Errorf("%T: Count should return 1", s)
+		}
+	}
+}
+
+func Test_Partition(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4, 5) {
+		in, out := Partition(s, isEven)
+		assertEqual(in, makeSet([]int{2, 4}), t)
+		assertEqual(out, makeSet([]int{1, 3, 5}), t)
+		if reflect.TypeOf(in) != reflect.TypeOf(s) || reflect.TypeOf(out) != reflect.TypeOf(s) {
+			t.Errorf("Partition on %T returned %T and %T", s, in, out)
+		}
+	}
+}
+
+func Test_FunctionalNilAndViews(t *testing.T) {
+	if Count(nil, isEven) != 0 || Filter(nil, isEven).RetElementCount() != 0 || !Every(nil, isEven) {
+		t.Error("a nil set should be treated as empty")
+	}
+	view := ReadOnly(makeSet([]int{1, 2, 3, 4}))
+	if Count(view, isEven) != 2 || !Any(NewPersistentSet(2), isEven) {
+		t.Error("read-only sets should work with the functional helpers")
+	}
+}
+
+func Test_FunctionalCallbackCanUseSet(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3) {
+		// 线程安全的实现在锁外执行回调,回调中访问原集合不会死锁
+		filtered := Filter(s, func(elem interface{}) bool {
+			return s.Contains(elem.(int) + 1)
+		})
+		assertEqual(filtered, makeSet([]int{1, 2}), t)
+	}
+}
+
+func Test_GroupBy(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4, 5, 6, 7) {
+		groups := GroupBy(s, func(elem interface{}) interface{} {
+			return elem.(int) % 3
+		})
+		if len(groups) != 3 {
+			t.Fatalf("%T: expected 3 groups, got %d", s, len(groups))
+		}
+		assertEqual(groups[0], makeSet([]int{3, 6}), t)
+		assertEqual(groups[1], makeSet([]int{1, 4, 7}), t)
+		assertEqual(groups[2], makeSet([]int{2, 5}), t)
+
+		for _, bucket := range groups {
+			if reflect.TypeOf(bucket) != reflect.TypeOf(s) {
+				t.Errorf("GroupBy on %T returned a %T bucket", s, bucket)
+			}
+		}
+	}
+}
+
+func Test_IndexBy(t *testing.T) {
+	type host struct {
+		name    string
+		regions string
+	}
+	hosts := NewMapSet(host{"a", "eu"}, host{"b", "us"}, host{"c", "eu,us"})
+
+	idx := IndexBy(hosts, func(elem interface{}) []interface{} {
+		switch elem.(host).regions {
+		case "eu,us":
+			return []interface{}{"eu", "us"}
+		default:
+			return []interface{}{elem.(host).regions}
+		}
+	})
+
+	assertEqual(idx.Get("eu"), NewMapSet(host{"a", "eu"}, host{"c", "eu,us"}), t)
+	assertEqual(idx.Get("us"), NewMapSet(host{"b", "us"}, host{"c", "eu,us"}), t)
+	if idx.Get("ap") != nil {
+		t.Error("a missing key should return nil")
+	}
+	if !NewThreadUnsafeSetFromSlice(idx.Keys()).Equal(NewMapSet("eu", "us")) {
+		t.Errorf("unexpected keys %v", idx.Keys())
+	}
+	if _, ok := idx.Get("eu").(*threadSafeSet); !ok {
+		t.Error("IndexBy buckets should be the same kind as the source set")
+	}
+}
@@ -0,0 +1,303 @@
+package mapSet
+
+import (
+	"iter"
+	"reflect"
+)
+
+// Set是MapSet的泛型版本,元素类型由T在编译期确定,调用方无需再做类型断言。
+// Set是对MapSet的类型化包装,与MapSet一样分为线程安全,线程不安全两种实现
+type Set[T comparable] interface {
+	// Add给Set中添加一个元素
+	Add(v T) bool
+
+	// 返回Set中的元素个数
+	RetElementCount() int
+
+	// 清空Set中的所有元素
+	Clear()
+
+	// 复制所有的键值克隆一个相同的Set
+	Clone() Set[T]
+
+	// 给定一系列元素，判断这些元素是否都在Set中
+	Contains(v ...T) bool
+
+	// 从Set中删除一个元素
+	Remove(v T)
+
+	// 随机返回Set中的一个元素,注意并不是弹出。Set为空时第二个返回值为false
+	RandomReturn() (T, bool)
+
+	// 把Set中的成员作为切片返回
+	ToSlice() []T
+
+	// 判断两个Set是否相等,other可以是任意一种Set实现,nil视为空集
+	Equal(other Set[T]) bool
+
+	// 遍历Set中的每个元素,并对每个元素传递一个方法，如果传递的 func 返回 true，则停止迭代
+	Each(func(T) bool)
+
+	// 返回Set的所有Key组成的字符串，可以指定sep为分隔字符
+	String(sep string) string
+
+	// Set中随机返回一个元素,并在Set中删除这个元素。Set为空时第二个返回值为false
+	Pop() (T, bool)
+
+	// 返回两个Set的并集,结果与调用者是同一种实现
+	Union(other Set[T]) Set[T]
+
+	// 返回两个Set的交集,结果与调用者是同一种实现
+	Intersect(other Set[T]) Set[T]
+
+	// 返回在调用者中但不在other中的元素组成的差集
+	Difference(other Set[T]) Set[T]
+
+	// 返回只在其中一个Set中出现的元素组成的对称差集
+	SymmetricDifference(other Set[T]) Set[T]
+
+	// 判断调用者是否为other的子集
+	IsSubset(other Set[T]) bool
+
+	// 判断调用者是否为other的真子集
+	IsProperSubset(other Set[T]) bool
+
+	// 判断调用者是否为other的超集
+	IsSuperset(other Set[T]) bool
+
+	// 判断调用者是否为other的真超集
+	IsProperSuperset(other Set[T]) bool
+
+	// 判断两个Set是否没有任何共同元素
+	IsDisjoint(other Set[T]) bool
+
+	// 添加多个元素,返回新加入的元素个数
+	AddAll(vals ...T) int
+
+	// 删除多个元素,返回实际删除的元素个数
+	RemoveAll(vals ...T) int
+
+	// 返回可用于for range的iter.Seq,每次range开始时遍历当时的快照
+	All() iter.Seq[T]
+
+	// 返回满足pred的元素组成的新Set,结果与调用者是同一种实现
+	Filter(pred func(T) bool) Set[T]
+
+	// 与MapSet相同,把Set编码为JSON数组
+	MarshalJSON() ([]byte, error)
+
+	// 把JSON数组中的元素按T解码后加入Set,元素不能解码为T时返回错误且不修改Set
+	UnmarshalJSON(b []byte) error
+}
+
+// genericSet把Set[T]的方法转换为对被包装MapSet的调用,
+// 只通过Set[T]加入元素,因此s中的元素都是T类型
+type genericSet[T comparable] struct {
+	s MapSet
+}
+
+// NewGenericSet创建并返回一个线程安全的Set,对应NewMapSet
+func NewGenericSet[T comparable](vals ...T) Set[T] {
+	set := &genericSet[T]{s: NewMapSet()}
+	set.AddAll(vals...)
+	return set
+}
+
+// NewGenericSetFromSlice从给定的s切片中添加元素
+func NewGenericSetFromSlice[T comparable](s []T) Set[T] {
+	return NewGenericSet(s...)
+}
+
+// NewThreadUnsafeGenericSet创建并返回一个线程不安全的Set,对应NewThreadUnsafeSet
+func NewThreadUnsafeGenericSet[T comparable](vals ...T) Set[T] {
+	set := &genericSet[T]{s: NewThreadUnsafeSet()}
+	set.AddAll(vals...)
+	return set
+}
+
+// NewThreadUnsafeGenericSetFromSlice根据参数s初始化一个线程不安全的Set
+func NewThreadUnsafeGenericSetFromSlice[T comparable](s []T) Set[T] {
+	return NewThreadUnsafeGenericSet(s...)
+}
+
+// isNilGenericSet判断s是否为nil,包括持有nil指针的接口值
+func isNilGenericSet[T comparable](s Set[T]) bool {
+	if s == nil {
+		return true
+	}
+	v := reflect.ValueOf(s)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// untyped返回与other元素相同的MapSet:genericSet直接使用被包装的MapSet,
+// 其他实现先复制元素,nil原样返回,由MapSet按空集处理
+func untyped[T comparable](other Set[T]) MapSet {
+	if isNilGenericSet(other) {
+		return nil
+	}
+	if o, ok := other.(*genericSet[T]); ok {
+		return o.s
+	}
+	return NewThreadUnsafeSetFromSlice(toInterfaces(other.ToSlice()))
+}
+
+// typed把元素转换为T。T是接口类型时元素可能为nil,此时返回T的零值
+func typed[T comparable](v interface{}) T {
+	ret, _ := v.(T)
+	return ret
+}
+
+func typedSlice[T comparable](vals []interface{}) []T {
+	ret := make([]T, len(vals))
+	for i, v := range vals {
+		ret[i] = typed[T](v)
+	}
+	return ret
+}
+
+func toInterfaces[T comparable](vals []T) []interface{} {
+	ret := make([]interface{}, len(vals))
+	for i, v := range vals {
+		ret[i] = v
+	}
+	return ret
+}
+
+func (set *genericSet[T]) wrap(s MapSet) Set[T] {
+	return &genericSet[T]{s: s}
+}
+
+func (set *genericSet[T]) Add(v T) bool {
+	return set.s.Add(v)
+}
+
+func (set *genericSet[T]) RetElementCount() int {
+	return set.s.RetElementCount()
+}
+
+func (set *genericSet[T]) Clear() {
+	set.s.Clear()
+}
+
+func (set *genericSet[T]) setRandSource(r *randSource) {
+	if setter, ok := set.s.(randSourceSetter); ok {
+		setter.setRandSource(r)
+	}
+}
+
+func (set *genericSet[T]) Clone() Set[T] {
+	return set.wrap(set.s.Clone())
+}
+
+func (set *genericSet[T]) Contains(v ...T) bool {
+	return set.s.Contains(toInterfaces(v)...)
+}
+
+func (set *genericSet[T]) Remove(v T) {
+	set.s.Remove(v)
+}
+
+// RandomReturn和Pop通过切片返回元素,以区分空集与值为nil的元素
+func (set *genericSet[T]) RandomReturn() (T, bool) {
+	items := set.s.SampleWithReplacement(1)
+	if len(items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return typed[T](items[0]), true
+}
+
+func (set *genericSet[T]) ToSlice() []T {
+	return typedSlice[T](set.s.ToSlice())
+}
+
+func (set *genericSet[T]) Equal(other Set[T]) bool {
+	return set.s.Equal(untyped(other))
+}
+
+func (set *genericSet[T]) Each(cb func(T) bool) {
+	set.s.Each(func(elem interface{}) bool {
+		return cb(typed[T](elem))
+	})
+}
+
+func (set *genericSet[T]) String(sep string) string {
+	return set.s.String(sep)
+}
+
+func (set *genericSet[T]) Pop() (T, bool) {
+	items := set.s.PopN(1)
+	if len(items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return typed[T](items[0]), true
+}
+
+func (set *genericSet[T]) Union(other Set[T]) Set[T] {
+	return set.wrap(set.s.Union(untyped(other)))
+}
+
+func (set *genericSet[T]) Intersect(other Set[T]) Set[T] {
+	return set.wrap(set.s.Intersect(untyped(other)))
+}
+
+func (set *genericSet[T]) Difference(other Set[T]) Set[T] {
+	return set.wrap(set.s.Difference(untyped(other)))
+}
+
+func (set *genericSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return set.wrap(set.s.SymmetricDifference(untyped(other)))
+}
+
+func (set *genericSet[T]) IsSubset(other Set[T]) bool {
+	return set.s.IsSubset(untyped(other))
+}
+
+func (set *genericSet[T]) IsProperSubset(other Set[T]) bool {
+	return set.s.IsProperSubset(untyped(other))
+}
+
+func (set *genericSet[T]) IsSuperset(other Set[T]) bool {
+	return set.s.IsSuperset(untyped(other))
+}
+
+func (set *genericSet[T]) IsProperSuperset(other Set[T]) bool {
+	return set.s.IsProperSuperset(untyped(other))
+}
+
+func (set *genericSet[T]) IsDisjoint(other Set[T]) bool {
+	return set.s.IsDisjoint(untyped(other))
+}
+
+func (set *genericSet[T]) AddAll(vals ...T) int {
+	return set.s.AddAll(toInterfaces(vals)...)
+}
+
+func (set *genericSet[T]) RemoveAll(vals ...T) int {
+	return set.s.RemoveAll(toInterfaces(vals)...)
+}
+
+func (set *genericSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for elem := range set.s.All() {
+			if !yield(typed[T](elem)) {
+				return
+			}
+		}
+	}
+}
+
+func (set *genericSet[T]) Filter(pred func(T) bool) Set[T] {
+	return set.wrap(Filter(set.s, func(elem interface{}) bool {
+		return pred(typed[T](elem))
+	}))
+}
+
+func (set *genericSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSONElems(set.s.ToSlice())
+}
+
+func (set *genericSet[T]) UnmarshalJSON(b []byte) error {
+	return UnmarshalJSONWith(b, set.s, DecodeAs[T]())
+}
@@ -0,0 +1,183 @@
+package mapSet
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func Test_GenericNewSet(t *testing.T) {
+	a := NewGenericSet[int]()
+	if a.RetElementCount() != 0 {
+		t.Error("NewGenericSet should start out as an empty set")
+	}
+
+	if !NewGenericSetFromSlice([]string{"a", "b"}).Equal(NewGenericSet("b", "a")) {
+		t.Error("sets built from the same elements should be equal")
+	}
+	if !NewThreadUnsafeGenericSetFromSlice([]int{1, 2}).Equal(NewThreadUnsafeGenericSet(2, 1)) {
+		t.Error("sets built from the same elements should be equal")
+	}
+}
+
+func Test_GenericAddRemoveContains(t *testing.T) {
+	for _, a := range []Set[int]{NewGenericSet[int](), NewThreadUnsafeGenericSet[int]()} {
+		a.Add(7)
+		a.Add(5)
+		a.Add(3)
+		a.Add(7)
+
+		if a.RetElementCount() != 3 {
+			t.Errorf("%T should have 3 elements since 7 is a duplicate", a)
+		}
+		if !a.Contains(7, 5, 3) {
+			t.Errorf("%T should have a 7, 5, and 3 in it", a)
+		}
+
+		a.Remove(5)
+		if a.Contains(5) || a.RetElementCount() != 2 {
+			t.Errorf("%T should not contain 5 after removing it", a)
+		}
+
+		a.Clear()
+		if a.RetElementCount() != 0 {
+			t.Errorf("%T should be empty after Clear", a)
+		}
+	}
+}
+
+func Test_GenericPop(t *testing.T) {
+	for _, a := range []Set[string]{NewGenericSet("a", "b"), NewThreadUnsafeGenericSet("a", "b")} {
+		captured := NewThreadUnsafeGenericSet[string]()
+		for i := 0; i < 2; i++ {
+			v, ok := a.Pop()
+			if !ok {
+				t.Fatalf("%T Pop should succeed while the set is not empty", a)
+			}
+			captured.Add(v)
+		}
+		if v, ok := a.Pop(); ok || v != "" {
+			t.Errorf("%T Pop on an empty set should return the zero value and false", a)
+		}
+		if !captured.Contains("a", "b") {
+			t.Errorf("%T Pop should have returned a and b", a)
+		}
+	}
+}
+
+func Test_GenericSetAlgebra(t *testing.T) {
+	a := NewGenericSet(1, 2, 3)
+	b := NewThreadUnsafeGenericSet(2, 3, 4)
+
+	if !a.Union(b).Equal(NewGenericSet(1, 2, 3, 4)) {
+		t.Error("the union of a and b should be 1, 2, 3, 4")
+	}
+	if !b.Intersect(a).Equal(NewGenericSet(2, 3)) {
+		t.Error("the intersection of a and b should be 2, 3")
+	}
+	if !a.Difference(b).Equal(NewGenericSet(1)) {
+		t.Error("the difference of a to b should be 1")
+	}
+	if !b.SymmetricDifference(a).Equal(NewGenericSet(1, 4)) {
+		t.Error("the symmetric difference of a and b should be 1, 4")
+	}
+	if _, ok := a.Union(b).(*genericSet[int]).s.(*threadSafeSet); !ok {
+		t.Error("the union should keep the receiver's implementation")
+	}
+	if !a.Union(nil).Equal(a) {
+		t.Error("the union with nil should equal the receiver")
+	}
+}
+
+func Test_GenericSetPredicates(t *testing.T) {
+	a := NewGenericSet(1, 2, 3)
+	b := NewThreadUnsafeGenericSet(1, 2)
+
+	if !b.IsProperSubset(a) || !a.IsProperSuperset(b) {
+		t.Error("b should be a proper subset of a")
+	}
+	if !a.IsSubset(a) || a.IsProperSubset(a) {
+		t.Error("a should be a subset but not a proper subset of itself")
+	}
+	if a.IsDisjoint(b) || !a.IsDisjoint(NewGenericSet(9)) {
+		t.Error("IsDisjoint returned the wrong result")
+	}
+}
+
+func Test_GenericUnionConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss := NewGenericSet[int](), NewGenericSet[int]()
+	for i := 0; i < N; i++ {
+		s.Add(i)
+		ss.Add(i)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < N; i++ {
+		wg.Add(3)
+		go func() {
+			s.Union(ss)
+			wg.Done()
+		}()
+		go func() {
+			ss.Union(s)
+			wg.Done()
+		}()
+		go func(i int) {
+			s.Add(i + N)
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+}
+
+func Test_GenericBulkAndIteration(t *testing.T) {
+	for _, a := range []Set[int]{NewGenericSet[int](), NewThreadUnsafeGenericSet[int]()} {
+		if a.AddAll(1, 2, 3, 4, 1) != 4 || a.RemoveAll(4, 5) != 1 {
+			t.Errorf("%T: unexpected bulk results, got %v", a, a.ToSlice())
+		}
+
+		sum := 0
+		for v := range a.All() {
+			sum += v
+		}
+		if sum != 6 {
+			t.Errorf("%T: expected the elements to sum to 6, got %d", a, sum)
+		}
+
+		odd := a.Filter(func(v int) bool { return v%2 == 1 })
+		if !odd.Equal(NewGenericSet(1, 3)) {
+			t.Errorf("%T: unexpected filter result %v", a, odd.ToSlice())
+		}
+	}
+}
+
+func Test_GenericJSON(t *testing.T) {
+	a := NewGenericSet(1, 2, 3)
+	b, err := a.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := NewThreadUnsafeGenericSet[int]()
+	if err := decoded.UnmarshalJSON(b); err != nil || !decoded.Equal(a) {
+		t.Errorf("round-trip should keep int elements, got %v, %v", decoded.ToSlice(), err)
+	}
+	if err := decoded.UnmarshalJSON([]byte(`[4, "x"]`)); err == nil || decoded.Contains(4) {
+		t.Error("elements that don't decode as T should return an error and leave the set unchanged")
+	}
+}
+
+func Test_GenericNilElement(t *testing.T) {
+	a := NewThreadUnsafeGenericSet[interface{}](nil)
+	if v, ok := a.RandomReturn(); !ok || v != nil {
+		t.Errorf("expected to return the nil element, got %v, %v", v, ok)
+	}
+	if v, ok := a.Pop(); !ok || v != nil || a.RetElementCount() != 0 {
+		t.Errorf("expected to pop the nil element, got %v, %v", v, ok)
+	}
+	if _, ok := a.Pop(); ok {
+		t.Error("Pop on an empty set should return false")
+	}
+}
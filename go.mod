@@ -1,3 +1,3 @@
 module mapSet
 
-go 1.17
+go 1.24
@@ -0,0 +1,67 @@
+package mapSet
+
+import "iter"
+
+// Iterator是MapSet的迭代器,可以提前结束,也可以在任意时刻暂停后继续调用Next。
+//
+// Iterator遍历的是调用MapSet.Iterator时的快照:之后对集合的增删不会反映到迭代器中,
+// 迭代器也不持有集合的锁,因此遍历过程中可以放心地修改集合,
+// 也不会因为迭代器没有走完而阻塞其他goroutine的写操作。
+// 有序集合的快照保持集合自身的遍历顺序。Iterator本身不是并发安全的
+type Iterator struct {
+	items []interface{}
+	pos   int
+	value interface{}
+}
+
+func newIterator(items []interface{}) *Iterator {
+	return &Iterator{items: items}
+}
+
+// Next移动到下一个元素,没有更多元素或已经调用过Stop时返回false
+func (it *Iterator) Next() bool {
+	if it.pos >= len(it.items) {
+		it.Stop()
+		return false
+	}
+	it.value = it.items[it.pos]
+	it.pos++
+	return true
+}
+
+// Value返回Next移动到的当前元素
+func (it *Iterator) Value() interface{} {
+	return it.value
+}
+
+// Stop提前结束迭代并释放快照,之后Next总是返回false
+func (it *Iterator) Stop() {
+	it.items = nil
+	it.pos = 0
+	it.value = nil
+}
+
+// snapshotSeq返回一个iter.Seq,每次range开始时调用snapshot取得快照并遍历,
+// 遍历过程中不持有集合的锁
+func snapshotSeq(snapshot func() []interface{}) iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for _, elem := range snapshot() {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// eachMutable是不带锁实现的EachMutable:先取得元素快照,
+// 遍历时跳过已经被回调删除的元素
+func eachMutable(set MapSet, cb func(elem interface{}, set MapSet) bool) {
+	for _, elem := range set.ToSlice() {
+		if !set.Contains(elem) {
+			continue
+		}
+		if cb(elem, set) {
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package mapSet
+
+import (
+	"testing"
+	"time"
+)
+
+func allSetKinds(ints ...int) []MapSet {
+	sets := []MapSet{
+		makeSet(ints),
+		makeUnsafeSet(ints),
+		NewOrderedSet(FIFO),
+		NewThreadUnsafeOrderedSet(FIFO),
+		NewSortedSet(OrderedComparator[int]()),
+		NewThreadUnsafeSortedSet(OrderedComparator[int]()),
+		NewCopyOnWriteSet(),
+		NewShardedSet(4),
+	}
+	for _, s := range sets[2:] {
+		for _, i := range ints {
+			s.Add(i)
+		}
+	}
+	return sets
+}
+
+func Test_Iterator(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		it := s.Iterator()
+		seen := NewThreadUnsafeSet()
+		for it.Next() {
+			seen.Add(it.Value())
+		}
+		if !seen.Equal(s) {
+			t.Errorf("%T: iterator should visit every element, got %v", s, seen.ToSlice())
+		}
+		if it.Next() {
+			t.Errorf("%T: Next should keep returning false after the end", s)
+		}
+	}
+}
+
+func Test_IteratorResumeAndStop(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		it := s.Iterator()
+		if !it.Next() || !it.Next() {
+			t.Fatalf("%T: expected at least two elements", s)
+		}
+
+		// 暂停期间修改集合不会影响迭代器,也不会被迭代器的锁阻塞
+		s.Add(5)
+		s.Remove(it.Value())
+
+		count := 2
+		for it.Next() {
+			count++
+		}
+		if count != 4 {
+			t.Errorf("%T: iterator should traverse the snapshot of 4 elements, got %d", s, count)
+		}
+
+		it = s.Iterator()
+		it.Next()
+		it.Stop()
+		if it.Next() || it.Value() != nil {
+			t.Errorf("%T: Next should return false after Stop", s)
+		}
+	}
+}
+
+func Test_IteratorOrder(t *testing.T) {
+	s := NewOrderedSet(FIFO, "c", "a", "b")
+	var got []interface{}
+	for it := s.Iterator(); it.Next(); {
+		got = append(got, it.Value())
+	}
+	assertSliceEqual(got, []interface{}{"c", "a", "b"}, t)
+
+	got = got[:0]
+	for elem := range NewSortedSet(OrderedComparator[int](), 3, 1, 2).All() {
+		got = append(got, elem)
+	}
+	assertSliceEqual(got, []interface{}{1, 2, 3}, t)
+}
+
+func Test_AllEarlyExit(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		count := 0
+		for range s.All() {
+			count++
+			if count == 2 {
+				break
+			}
+		}
+		if count != 2 {
+			t.Errorf("%T: range over All should stop on break", s)
+		}
+	}
+}
+
+func Test_AllMutateInLoop(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		done := make(chan struct{})
+		go func() {
+			for elem := range s.All() {
+				s.Remove(elem)
+				s.Add(elem.(int) + 100)
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%T: mutating the set inside range over All deadlocked", s)
+		}
+		assertEqual(s, makeSet([]int{101, 102, 103, 104}), t)
+	}
+}
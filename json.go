@@ -0,0 +1,167 @@
+package mapSet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MarshalJSONSorted与集合自身的MarshalJSON相同,但会对元素排序,保证每次输出一致,
+// 适用于API响应和golden文件测试。排序规则为:布尔值 < 数字 < 字符串 < 其他类型,
+// 同类之间按值比较,其他类型按编码后的JSON文本比较
+func MarshalJSONSorted(set MapSet) ([]byte, error) {
+	if isNilSet(set) {
+		return []byte("null"), nil
+	}
+
+	elems := set.ToSlice()
+	items := make([]sortableJSONElem, 0, len(elems))
+	for _, elem := range elems {
+		b, err := json.Marshal(elem)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, newSortableJSONElem(elem, string(b)))
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].less(items[j])
+	})
+
+	encoded := make([]string, 0, len(items))
+	for _, item := range items {
+		encoded = append(encoded, item.encoded)
+	}
+	return []byte(fmt.Sprintf("[%s]", strings.Join(encoded, ","))), nil
+}
+
+// SortedJSON包装一个MapSet,使其在作为结构体字段等场景被json.Marshal编码时
+// 使用MarshalJSONSorted输出排序后的数组
+type SortedJSON struct {
+	MapSet
+}
+
+func (s SortedJSON) MarshalJSON() ([]byte, error) {
+	return MarshalJSONSorted(s.MapSet)
+}
+
+// UnmarshalJSON把JSON数组解码进被包装的集合,未设置集合时使用NewMapSet创建
+func (s *SortedJSON) UnmarshalJSON(b []byte) error {
+	if isNilSet(s.MapSet) {
+		s.MapSet = NewMapSet()
+	}
+	u, ok := s.MapSet.(json.Unmarshaler)
+	if !ok {
+		return fmt.Errorf("mapSet: %T does not implement json.Unmarshaler", s.MapSet)
+	}
+	return u.UnmarshalJSON(b)
+}
+
+// JSONDecodeFunc把JSON数组中的单个元素解码为要放入集合的值
+type JSONDecodeFunc func(raw json.RawMessage) (interface{}, error)
+
+// DecodeAs返回一个把元素解码为T类型的JSONDecodeFunc,
+// 例如DecodeAs[int]可以让MarshalJSON得到的整数集合原样还原,而不是变成json.Number
+func DecodeAs[T any]() JSONDecodeFunc {
+	return func(raw json.RawMessage) (interface{}, error) {
+		var v T
+		d := json.NewDecoder(bytes.NewReader(raw))
+		d.DisallowUnknownFields()
+		if err := d.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// UnmarshalJSONWith把JSON数组逐个元素交给decode解码后加入set。
+// 与集合自身的UnmarshalJSON不同,它不会跳过数组和对象元素:
+// decode返回错误或解码结果不能作为map的键时返回错误,此时set不会被修改
+func UnmarshalJSONWith(b []byte, set MapSet, decode JSONDecodeFunc) error {
+	if isNilSet(set) {
+		return fmt.Errorf("mapSet: UnmarshalJSONWith into nil set")
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(b, &raws); err != nil {
+		return err
+	}
+
+	elems := make([]interface{}, 0, len(raws))
+	for i, raw := range raws {
+		v, err := decode(raw)
+		if err != nil {
+			return fmt.Errorf("mapSet: decoding element %d (%s): %w", i, raw, err)
+		}
+		if !isComparableElem(v) {
+			return fmt.Errorf("mapSet: element %d (%s) decodes to unhashable type %T", i, raw, v)
+		}
+		elems = append(elems, v)
+	}
+
+	for _, v := range elems {
+		set.Add(v)
+	}
+	return nil
+}
+
+const (
+	jsonRankBool = iota
+	jsonRankNumber
+	jsonRankString
+	jsonRankOther
+)
+
+type sortableJSONElem struct {
+	rank    int
+	num     float64
+	str     string
+	encoded string
+}
+
+func newSortableJSONElem(elem interface{}, encoded string) sortableJSONElem {
+	item := sortableJSONElem{rank: jsonRankOther, encoded: encoded}
+	if n, ok := elem.(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			item.rank, item.num = jsonRankNumber, f
+		}
+		return item
+	}
+
+	v := reflect.ValueOf(elem)
+	switch v.Kind() {
+	case reflect.Bool:
+		item.rank = jsonRankBool
+		if v.Bool() {
+			item.num = 1
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		item.rank, item.num = jsonRankNumber, float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		item.rank, item.num = jsonRankNumber, float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		item.rank, item.num = jsonRankNumber, v.Float()
+	case reflect.String:
+		item.rank, item.str = jsonRankString, v.String()
+	}
+	return item
+}
+
+func (a sortableJSONElem) less(b sortableJSONElem) bool {
+	if a.rank != b.rank {
+		return a.rank < b.rank
+	}
+	switch a.rank {
+	case jsonRankBool, jsonRankNumber:
+		if a.num != b.num {
+			return a.num < b.num
+		}
+	case jsonRankString:
+		if a.str != b.str {
+			return a.str < b.str
+		}
+	}
+	return a.encoded < b.encoded
+}
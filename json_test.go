@@ -0,0 +1,144 @@
+package mapSet
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_MarshalJSON(t *testing.T) {
+	for _, s := range []MapSet{NewMapSet("a", "b"), NewThreadUnsafeSetFromSlice([]interface{}{"a", "b"})} {
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("%T: unexpected error: %v", s, err)
+		}
+		if string(b) != `["a","b"]` && string(b) != `["b","a"]` {
+			t.Errorf("%T: unexpected encoding %s", s, b)
+		}
+	}
+}
+
+func Test_UnmarshalJSON(t *testing.T) {
+	expected := NewMapSet("a", "b", json.Number("1"))
+
+	for _, s := range []MapSet{NewMapSet(), NewThreadUnsafeSet()} {
+		if err := json.Unmarshal([]byte(`["a", "b", 1]`), s); err != nil {
+			t.Fatalf("%T: unexpected error: %v", s, err)
+		}
+		assertEqual(s, expected, t)
+	}
+}
+
+func Test_JSONRoundTripSafe(t *testing.T) {
+	a := NewMapSet("x", "y", "z")
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewMapSet()
+	if err := json.Unmarshal(b, c); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(a, c, t)
+}
+
+func Test_MarshalJSONSorted(t *testing.T) {
+	for _, s := range []MapSet{
+		NewMapSet(10, "b", 2, true, "a", false, 1.5),
+		NewThreadUnsafeSetFromSlice([]interface{}{10, "b", 2, true, "a", false, 1.5}),
+	} {
+		b, err := MarshalJSONSorted(s)
+		if err != nil {
+			t.Fatalf("%T: unexpected error: %v", s, err)
+		}
+		if string(b) != `[false,true,1.5,2,10,"a","b"]` {
+			t.Errorf("%T: unexpected sorted encoding %s", s, b)
+		}
+	}
+
+	b, err := MarshalJSONSorted(nil)
+	if err != nil || string(b) != "null" {
+		t.Errorf("nil set should encode as null, got %s, %v", b, err)
+	}
+}
+
+func Test_SortedJSON(t *testing.T) {
+	type response struct {
+		Scopes SortedJSON `json:"scopes"`
+	}
+
+	b, err := json.Marshal(response{Scopes: SortedJSON{NewMapSet("write", "admin", "read")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"scopes":["admin","read","write"]}` {
+		t.Errorf("unexpected encoding %s", b)
+	}
+
+	var r response
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(r.Scopes.MapSet, NewMapSet("admin", "read", "write"), t)
+}
+
+func Test_UnmarshalJSONWithTyped(t *testing.T) {
+	for _, a := range []MapSet{NewMapSet(1, 2, 3), NewThreadUnsafeSetFromSlice([]interface{}{1, 2, 3})} {
+		b, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c := NewThreadUnsafeSet()
+		if err := UnmarshalJSONWith(b, c, DecodeAs[int]()); err != nil {
+			t.Fatalf("%T: unexpected error: %v", a, err)
+		}
+		assertEqual(a, c, t)
+	}
+}
+
+func Test_UnmarshalJSONWithStruct(t *testing.T) {
+	type host struct {
+		Name   string
+		Region string
+	}
+
+	s := NewMapSet()
+	err := UnmarshalJSONWith([]byte(`[{"Name":"a","Region":"eu"},{"Name":"b","Region":"us"}]`), s, DecodeAs[host]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Contains(host{"a", "eu"}, host{"b", "us"}) {
+		t.Errorf("unexpected decoded set %v", s.ToSlice())
+	}
+}
+
+func Test_UnmarshalJSONWithErrors(t *testing.T) {
+	s := NewMapSet()
+
+	if err := UnmarshalJSONWith([]byte(`[1, "x"]`), s, DecodeAs[int]()); err == nil {
+		t.Error("decoding a string as int should fail")
+	}
+	if err := UnmarshalJSONWith([]byte(`[1, [2, 3]]`), s, DecodeAs[interface{}]()); err == nil {
+		t.Error("decoding an array element should fail instead of being dropped")
+	}
+	// 静态类型可比较,但字段或元素中保存了切片
+	if err := UnmarshalJSONWith([]byte(`[{"X":[1,2]}]`), s, DecodeAs[struct{ X interface{} }]()); err == nil {
+		t.Error("a struct holding a slice should fail instead of panicking")
+	}
+	if err := UnmarshalJSONWith([]byte(`[[1,[2]]]`), s, DecodeAs[[2]interface{}]()); err == nil {
+		t.Error("an array holding a slice should fail instead of panicking")
+	}
+	if s.RetElementCount() != 0 {
+		t.Error("a failed decode should leave the set unchanged")
+	}
+
+	custom := func(raw json.RawMessage) (interface{}, error) {
+		return "id:" + string(raw), nil
+	}
+	if err := UnmarshalJSONWith([]byte(`[1, 2]`), s, custom); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(s, NewMapSet("id:1", "id:2"), t)
+}
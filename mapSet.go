@@ -1,45 +1,147 @@
 package mapSet
 
-// 存在的问题：
-// 1: 存储结构为map[interface{}]struct{},虽然元素加入到map中就是遍历时就是无序的,但这并不是真正的随机。
+import (
+	"iter"
+	"reflect"
+)
+
+// 说明：
+// 1: 存储结构为map加一个紧密保存元素的切片,虽然元素加入到map中就是遍历时就是无序的,但这并不是真正的随机。
+//    因此RandomReturn和Pop不依赖map的遍历顺序,而是用random.go中的随机数源在切片中等概率地选取元素,时间为O(1)。
+//    默认使用无锁的math/rand/v2顶层函数;可以用SetRandSource或SetRandSeed给单个集合指定随机数源,
+//    种子与操作顺序相同时结果可复现。
+
+// ReadOnlySet是MapSet中只读取元素,不修改集合的方法。
+// 需要把集合交给不应修改它的代码时,可以用ReadOnly包装后传入。PersistentSet也实现了ReadOnlySet
+type ReadOnlySet interface {
+	// 返回MapSet中的元素个数
+	RetElementCount() int
+
+	// 给定一系列元素，判断这些元素是否都在MapSet中
+	Contains(i ...interface{}) bool
+
+	// 给定一系列元素，判断是否至少有一个在MapSet中,未给定元素时返回false
+	ContainsAny(items ...interface{}) bool
+
+	// 把MapSet中的成员作为切片返回
+	ToSlice() []interface{}
+
+	// 返回MapSet当前内容的不可变快照,元素相同的FrozenSet用==比较相等,可以作为map的键或MapSet的元素。
+	// 所有实现都只保存能用==比较的元素,因此Freeze不会panic
+	Freeze() FrozenSet
+
+	// 判断两个MapSet是否相等,如果元素数量相等且两个MapSet中的元素都是一一对应则两个MapSet相等。
+	// other可以是任意一种MapSet实现,也可以是ReadOnly视图或PersistentSet等ReadOnlySet,nil视为空集。
+	// 以下接受other的方法都是如此
+	Equal(other ReadOnlySet) bool
+
+	// 遍历MapSet中的每个元素,并对每个元素传递一个方法，如果传递的 func 返回 true，则停止迭代。
+	// 线程安全的实现在回调期间持有读锁,回调中修改MapSet会死锁,需要修改时请使用EachMutable
+	Each(func(interface{}) bool)
+
+	// 返回MapSet的所有Key组成的字符串，可以指定sep为分隔字符
+	String(sep string) string
 
-// 此接口分为线程安全,线程不安全两种实现
+	// 判断调用者是否为other的子集
+	IsSubset(other ReadOnlySet) bool
+
+	// 判断调用者是否为other的真子集
+	IsProperSubset(other ReadOnlySet) bool
+
+	// 判断调用者是否为other的超集
+	IsSuperset(other ReadOnlySet) bool
+
+	// 判断调用者是否为other的真超集
+	IsProperSuperset(other ReadOnlySet) bool
+
+	// 判断两个MapSet是否没有任何共同元素
+	IsDisjoint(other ReadOnlySet) bool
+
+	// 返回遍历MapSet当前快照的迭代器,迭代器不持有锁,可以暂停,继续或用Stop提前结束
+	Iterator() *Iterator
+
+	// 返回可用于for range的iter.Seq,每次range开始时遍历当时的快照,循环体中可以修改MapSet
+	All() iter.Seq[interface{}]
+}
+
+// 此接口分为线程安全,线程不安全两种实现。
+// 元素类型为interface{},为兼容已有代码而保留,新代码可以使用泛型版本Set[T]
 type MapSet interface {
+	ReadOnlySet
+
 	// Add给MapSet中添加一个元素
 	Add(i interface{}) bool
 
-	// 返回MapSet中的元素个数
-	RetElementCount() int
-
 	// 清空MapSet中的所有元素
 	Clear()
 
 	// 复制所有的键值克隆一个相同的MapSet
 	Clone() MapSet
 
-	// 给定一系列元素，判断这些元素是否都在MapSet中
-	Contains(i ...interface{}) bool
-
 	// 从MapSet中删除一个元素
 	Remove(i interface{})
 
-	// 随机返回MapSet中的一个元素,注意并不是弹出
+	// 与Remove相同,返回删除前元素是否在MapSet中
+	Delete(i interface{}) bool
+
+	// 添加多个元素,返回新加入的元素个数。线程安全的实现只加一次锁
+	AddAll(items ...interface{}) int
+
+	// 删除多个元素,返回实际删除的元素个数。线程安全的实现只加一次锁
+	RemoveAll(items ...interface{}) int
+
+	// 等概率地随机返回MapSet中的一个元素,注意并不是弹出。MapSet为空时返回nil
 	RandomReturn() interface{}
 
-	// 把MapSet中的成员作为切片返回
-	ToSlice() []interface{}
+	// MapSet中等概率地随机返回一个元素,并再MapSet中删除这个元素。MapSet为空时返回nil
+	Pop() interface{}
 
-	// 判断两个MapSet是否相等,如果元素数量相等且两个MapSet中的元素都是一一对应则两个MapSet相等
-	Equal(other MapSet) bool
+	// 返回两个MapSet的并集,结果与调用者是同一种实现
+	Union(other ReadOnlySet) MapSet
 
-	// 遍历MapSet中的每个元素,并对每个元素传递一个方法，如果传递的 func 返回 true，则停止迭代
-	Each(func(interface{}) bool)
+	// 返回两个MapSet的交集,结果与调用者是同一种实现
+	Intersect(other ReadOnlySet) MapSet
 
-	// 返回MapSet的所有Key组成的字符串，可以指定sep为分隔字符
-	String(sep string) string
+	// 返回在调用者中但不在other中的元素组成的差集
+	Difference(other ReadOnlySet) MapSet
 
-	// MapSet中随机返回一个元素,并再MapSet中删除这个元素
-	Pop() interface{}
+	// 返回只在其中一个MapSet中出现的元素组成的对称差集
+	SymmetricDifference(other ReadOnlySet) MapSet
+
+	// 把other中的元素并入调用者,返回新加入的元素个数
+	UnionWith(other ReadOnlySet) int
+
+	// 只保留同时在other中的元素,返回删除的元素个数
+	IntersectWith(other ReadOnlySet) int
+
+	// 删除同时在other中的元素,返回删除的元素个数
+	DifferenceWith(other ReadOnlySet) int
+
+	// 删除同时在other中的元素并加入只在other中的元素,返回增删的元素总数
+	SymmetricDifferenceWith(other ReadOnlySet) int
+
+	// 等概率地随机选出k个不同的元素,k大于元素个数时返回全部元素
+	Sample(k int) []interface{}
+
+	// 有放回地随机选出k个元素,结果中可能包含重复元素
+	SampleWithReplacement(k int) []interface{}
+
+	// 随机弹出k个不同的元素,k大于元素个数时弹出全部元素
+	PopN(k int) []interface{}
+
+	// 把MapSet中的成员按随机排列作为切片返回
+	Shuffled() []interface{}
+
+	// 删除所有满足pred的元素,返回删除的个数。线程安全的实现在一次写锁内完成,pred中不能再调用本MapSet的方法
+	RemoveIf(pred func(interface{}) bool) int
+
+	// 只保留满足pred的元素,返回删除的个数。线程安全的实现在一次写锁内完成,pred中不能再调用本MapSet的方法
+	RetainIf(pred func(interface{}) bool) int
+
+	// 在一次写锁内遍历开始时已有的每个元素,回调可以通过参数set增删元素:
+	// 被删除且尚未遍历到的元素会被跳过,新加入的元素不会被遍历。回调返回true时停止遍历。
+	// 参数set只在回调期间有效,回调中不能调用本MapSet自身的方法
+	EachMutable(cb func(elem interface{}, set MapSet) bool)
 }
 
 // NewMapSet创建并返回一个空的MapSet
@@ -75,4 +177,36 @@ func NewThreadUnsafeSetFromSlice(s []interface{}) MapSet {
 		a.Add(item)
 	}
 	return a
-}
\ No newline at end of file
+}
+
+// isNilSet判断s是否为nil,包括持有nil指针的接口值
+func isNilSet(s ReadOnlySet) bool {
+	return isNilValue(s)
+}
+
+// isNilValue判断v是否为nil,包括持有nil指针的接口值
+func isNilValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// orEmpty在s为nil时返回一个空集,使二元操作可以安全地处理nil参数。
+// s是ReadOnly视图时返回被包装的MapSet,使视图与集合本身一样参与加锁和是否为同一集合的判断
+func orEmpty(s ReadOnlySet) ReadOnlySet {
+	if isNilSet(s) {
+		return NewThreadUnsafeSet()
+	}
+	if r, ok := s.(readOnlySet); ok {
+		return r.s
+	}
+	return s
+}
+
+// isComparableElem判断v能否作为集合的元素,即能否用==比较和作为map的键。
+// 类型可比较的值仍可能在接口字段中保存切片等不可比较的值,因此按值而不是按类型判断
+func isComparableElem(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).Comparable()
+}
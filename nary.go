@@ -0,0 +1,94 @@
+package mapSet
+
+import "sort"
+
+// emptyLike返回一个与s同一种实现的空MapSet,s为nil或持有nil指针时返回NewMapSet()
+func emptyLike(s MapSet) MapSet {
+	if isNilSet(s) {
+		return NewMapSet()
+	}
+	switch t := s.(type) {
+	case *threadUnsafeSet:
+		return NewThreadUnsafeSet()
+	case *threadSafeSet:
+		return NewMapSet()
+	case *blockingSet:
+		return NewBlockingSet()
+	case *copyOnWriteSet:
+		return NewCopyOnWriteSet()
+	case *shardedSet:
+		return t.emptyLike()
+	case *threadUnsafeOrderedSet:
+		return NewThreadUnsafeOrderedSet(t.pop)
+	case *threadSafeOrderedSet:
+		t.RLock()
+		defer t.RUnlock()
+		return NewOrderedSet(t.s.pop)
+	case *threadUnsafeSortedSet:
+		return NewThreadUnsafeSortedSet(t.cmp)
+	case *threadSafeSortedSet:
+		t.RLock()
+		defer t.RUnlock()
+		return NewSortedSet(t.s.cmp)
+	}
+	ret := s.Clone()
+	ret.Clear()
+	return ret
+}
+
+// UnionAll返回所有集合的并集,结果与第一个集合是同一种实现,未给定集合时返回NewMapSet()。
+// 参数可以混合使用各种实现,nil视为空集
+func UnionAll(sets ...MapSet) MapSet {
+	if len(sets) == 0 {
+		return NewMapSet()
+	}
+
+	ret := emptyLike(sets[0])
+	for _, s := range sets {
+		ret.UnionWith(s)
+	}
+	return ret
+}
+
+// IntersectAll返回所有集合的交集,结果与第一个集合是同一种实现,未给定集合时返回NewMapSet()。
+// 从元素最少的集合开始逐个过滤候选元素,不产生中间集合,候选为空时立即结束。
+// 参数可以混合使用各种实现,nil视为空集
+func IntersectAll(sets ...MapSet) MapSet {
+	if len(sets) == 0 {
+		return NewMapSet()
+	}
+	ret := emptyLike(sets[0])
+
+	type sizedSet struct {
+		set MapSet
+		n   int
+	}
+	ordered := make([]sizedSet, 0, len(sets))
+	for _, s := range sets {
+		if isNilSet(s) {
+			return ret
+		}
+		ordered = append(ordered, sizedSet{s, s.RetElementCount()})
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].n < ordered[j].n
+	})
+
+	candidates := ordered[0].set.ToSlice()
+	for _, s := range ordered[1:] {
+		if len(candidates) == 0 {
+			break
+		}
+		n := 0
+		for _, elem := range candidates {
+			if s.set.Contains(elem) {
+				candidates[n] = elem
+				n++
+			}
+		}
+		candidates = candidates[:n]
+	}
+
+	ret.AddAll(candidates...)
+	return ret
+}
@@ -0,0 +1,369 @@
+package mapSet
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// EventType是集合变化事件的类型
+type EventType int
+
+const (
+	// Added表示Elem被加入集合
+	Added EventType = iota + 1
+	// Removed表示Elem被删除,包括Pop和PopN弹出的元素
+	Removed
+	// Cleared表示集合被Clear清空,此时Elem为nil
+	Cleared
+)
+
+func (t EventType) String() string {
+	switch t {
+	case Added:
+		return "Added"
+	case Removed:
+		return "Removed"
+	case Cleared:
+		return "Cleared"
+	}
+	return "Unknown"
+}
+
+// Event是ObservableSet发布的变化事件
+type Event struct {
+	Type EventType
+	Elem interface{}
+	// Seq是事件在所属集合中的序号,从1开始连续递增,订阅者可以据此发现被丢弃的事件
+	Seq uint64
+}
+
+// OverflowPolicy决定订阅者的缓冲区已满时如何处理新事件
+type OverflowPolicy int
+
+const (
+	// DropNewest丢弃新事件,订阅者收到的是较早的事件
+	DropNewest OverflowPolicy = iota
+	// DropOldest丢弃缓冲区中最早的事件,订阅者收到的是最新的事件
+	DropOldest
+)
+
+// defaultEventBuffer是Observe未指定缓冲区大小时每个订阅者的缓冲区大小
+const defaultEventBuffer = 64
+
+// ObservableSet在集合变化时向订阅者发布事件
+type ObservableSet interface {
+	MapSet
+
+	// 注册回调,之后的每个事件按发生顺序在单独的goroutine中传给fn,返回取消订阅的函数。
+	// fn处理较慢时事件在缓冲区中排队,缓冲区满时按OverflowPolicy丢弃
+	Subscribe(fn func(Event)) (cancel func())
+
+	// 返回接收之后事件的channel,ctx取消时取消订阅并关闭channel。
+	// channel的容量就是缓冲区大小,读取不及时时按OverflowPolicy丢弃事件
+	Watch(ctx context.Context) <-chan Event
+}
+
+// observableSet包装一个MapSet,mu使修改与事件发布在所有写者间串行,保证事件顺序与修改顺序一致。
+// 读操作直接转发给被包装的集合,不经过mu
+type observableSet struct {
+	MapSet
+	mu     sync.Mutex
+	seq    uint64
+	subs   map[chan Event]struct{}
+	buffer int
+	policy OverflowPolicy
+}
+
+// Observe返回s的可观察包装,通过包装进行的Add,Remove,Pop,Clear及各种批量修改都会发布事件。
+// 包装之后应只通过返回值修改s,直接修改s不会发布事件。Union,Clone等返回新集合的方法以及Filter等函数
+// 转发给s,结果与s是同一种实现,不再是可观察的。
+// buffer为每个订阅者的缓冲区大小,不大于0时使用默认值64;policy决定缓冲区满时丢弃哪个事件,
+// 因此订阅者处理较慢时不会阻塞写者。nil视为新的空MapSet
+func Observe(s MapSet, buffer int, policy OverflowPolicy) ObservableSet {
+	if isNilSet(s) {
+		s = NewMapSet()
+	}
+	if buffer <= 0 {
+		buffer = defaultEventBuffer
+	}
+	return &observableSet{
+		MapSet: s,
+		subs:   make(map[chan Event]struct{}),
+		buffer: buffer,
+		policy: policy,
+	}
+}
+
+// publishLocked依次发布给定类型的事件,调用者需要持有mu
+func (set *observableSet) publishLocked(typ EventType, elems ...interface{}) {
+	for _, elem := range elems {
+		set.seq++
+		ev := Event{Type: typ, Elem: elem, Seq: set.seq}
+		for ch := range set.subs {
+			set.send(ch, ev)
+		}
+	}
+}
+
+// send不阻塞地把ev放入ch。写者之间由mu串行,只有订阅者会同时从ch取走事件,
+// 因此DropOldest丢弃一个事件后一定有空位
+func (set *observableSet) send(ch chan Event, ev Event) {
+	select {
+	case ch <- ev:
+		return
+	default:
+	}
+	if set.policy == DropOldest {
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+func (set *observableSet) subscribe() chan Event {
+	ch := make(chan Event, set.buffer)
+	set.mu.Lock()
+	set.subs[ch] = struct{}{}
+	set.mu.Unlock()
+	return ch
+}
+
+func (set *observableSet) unsubscribe(ch chan Event) {
+	set.mu.Lock()
+	if _, ok := set.subs[ch]; ok {
+		delete(set.subs, ch)
+		close(ch)
+	}
+	set.mu.Unlock()
+}
+
+func (set *observableSet) Subscribe(fn func(Event)) (cancel func()) {
+	ch := set.subscribe()
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case ev, ok := <-ch:
+				if !ok {
+					return
+				}
+				fn(ev)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			set.unsubscribe(ch)
+		})
+	}
+}
+
+func (set *observableSet) Watch(ctx context.Context) <-chan Event {
+	ch := set.subscribe()
+	go func() {
+		<-ctx.Done()
+		set.unsubscribe(ch)
+	}()
+	return ch
+}
+
+func (set *observableSet) Add(i interface{}) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	ret := set.MapSet.Add(i)
+	if ret {
+		set.publishLocked(Added, i)
+	}
+	return ret
+}
+
+func (set *observableSet) Remove(i interface{}) {
+	set.Delete(i)
+}
+
+func (set *observableSet) Delete(i interface{}) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	ret := set.MapSet.Delete(i)
+	if ret {
+		set.publishLocked(Removed, i)
+	}
+	return ret
+}
+
+// setRandSource把随机数源交给被包装的集合,随机操作都由它完成
+func (set *observableSet) setRandSource(r *randSource) {
+	if setter, ok := set.MapSet.(randSourceSetter); ok {
+		setter.setRandSource(r)
+	}
+}
+
+func (set *observableSet) Clear() {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if set.MapSet.RetElementCount() == 0 {
+		return
+	}
+	set.MapSet.Clear()
+	set.publishLocked(Cleared, nil)
+}
+
+func (set *observableSet) Pop() interface{} {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if set.MapSet.RetElementCount() == 0 {
+		return nil
+	}
+	item := set.MapSet.Pop()
+	set.publishLocked(Removed, item)
+	return item
+}
+
+func (set *observableSet) PopN(k int) []interface{} {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	items := set.MapSet.PopN(k)
+	set.publishLocked(Removed, items...)
+	return items
+}
+
+// addAllLocked加入items中尚不存在的元素并发布事件,调用者需要持有mu
+func (set *observableSet) addAllLocked(items []interface{}) int {
+	added := make([]interface{}, 0, len(items))
+	seen := newThreadUnsafeSet()
+	for _, item := range items {
+		if seen.Add(item) && !set.MapSet.Contains(item) {
+			added = append(added, item)
+		}
+	}
+	set.MapSet.AddAll(added...)
+	set.publishLocked(Added, added...)
+	return len(added)
+}
+
+// removeAllLocked删除items中存在的元素并发布事件,调用者需要持有mu
+func (set *observableSet) removeAllLocked(items []interface{}) int {
+	removed := make([]interface{}, 0, len(items))
+	seen := newThreadUnsafeSet()
+	for _, item := range items {
+		if seen.Add(item) && set.MapSet.Contains(item) {
+			removed = append(removed, item)
+		}
+	}
+	set.MapSet.RemoveAll(removed...)
+	set.publishLocked(Removed, removed...)
+	return len(removed)
+}
+
+func (set *observableSet) AddAll(items ...interface{}) int {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	return set.addAllLocked(items)
+}
+
+func (set *observableSet) RemoveAll(items ...interface{}) int {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	return set.removeAllLocked(items)
+}
+
+// 以下原地运算先取得other的快照,再通过addAllLocked和removeAllLocked修改集合
+
+func (set *observableSet) UnionWith(other ReadOnlySet) int {
+	o := snapshotOther(other).ToSlice()
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	return set.addAllLocked(o)
+}
+
+func (set *observableSet) IntersectWith(other ReadOnlySet) int {
+	o := snapshotOther(other)
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	var removed []interface{}
+	for _, elem := range set.MapSet.ToSlice() {
+		if !o.Contains(elem) {
+			removed = append(removed, elem)
+		}
+	}
+	return set.removeAllLocked(removed)
+}
+
+func (set *observableSet) DifferenceWith(other ReadOnlySet) int {
+	o := snapshotOther(other).ToSlice()
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	return set.removeAllLocked(o)
+}
+
+func (set *observableSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	o := snapshotOther(other).ToSlice()
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	var added, removed []interface{}
+	for _, elem := range o {
+		if set.MapSet.Contains(elem) {
+			removed = append(removed, elem)
+		} else {
+			added = append(added, elem)
+		}
+	}
+	return set.removeAllLocked(removed) + set.addAllLocked(added)
+}
+
+func (set *observableSet) RemoveIf(pred func(interface{}) bool) int {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	var removed []interface{}
+	n := set.MapSet.RemoveIf(func(elem interface{}) bool {
+		if pred(elem) {
+			removed = append(removed, elem)
+			return true
+		}
+		return false
+	})
+	set.publishLocked(Removed, removed...)
+	return n
+}
+
+func (set *observableSet) RetainIf(pred func(interface{}) bool) int {
+	return set.RemoveIf(func(elem interface{}) bool {
+		return !pred(elem)
+	})
+}
+
+// EachMutable比较回调前后的元素发布事件
+func (set *observableSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	before := NewThreadUnsafeSetFromSlice(set.MapSet.ToSlice())
+	set.MapSet.EachMutable(cb)
+	after := NewThreadUnsafeSetFromSlice(set.MapSet.ToSlice())
+	set.publishLocked(Removed, before.Difference(after).ToSlice()...)
+	set.publishLocked(Added, after.Difference(before).ToSlice()...)
+}
+
+func (set *observableSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.MapSet)
+}
+
+// UnmarshalJSON解码JSON数组并加入其中的元素,新加入的元素会发布事件
+func (set *observableSet) UnmarshalJSON(b []byte) error {
+	decoded, err := unmarshalJSONPrimitives(b)
+	if err != nil {
+		return err
+	}
+	set.AddAll(decoded...)
+	return nil
+}
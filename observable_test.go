@@ -0,0 +1,210 @@
+package mapSet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collect从ch读取n个事件,超时时返回已读取的事件
+func collect(ch <-chan Event, n int) []Event {
+	var events []Event
+	for len(events) < n {
+		select {
+		case ev := <-ch:
+			events = append(events, ev)
+		case <-time.After(time.Second):
+			return events
+		}
+	}
+	return events
+}
+
+func Test_ObservableEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := Observe(NewMapSet(1), 0, DropNewest)
+	ch := s.Watch(ctx)
+
+	s.Add(2)
+	s.Add(2)
+	s.Remove(1)
+	s.Remove(1)
+	s.AddAll(3, 4, 3, 2)
+	s.RemoveAll(3, 5)
+	s.UnionWith(NewMapSet(5))
+	s.DifferenceWith(NewMapSet(5))
+	s.Clear()
+	s.Clear()
+
+	expected := []Event{
+		{Added, 2, 1},
+		{Removed, 1, 2},
+		{Added, 3, 3},
+		{Added, 4, 4},
+		{Removed, 3, 5},
+		{Added, 5, 6},
+		{Removed, 5, 7},
+		{Cleared, nil, 8},
+	}
+	events := collect(ch, len(expected))
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %v", len(expected), events)
+	}
+	for i, ev := range events {
+		if ev != expected[i] {
+			t.Errorf("event %d: expected %v, got %v", i, expected[i], ev)
+		}
+	}
+}
+
+func Test_ObservablePop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := Observe(NewThreadUnsafeSet(), 0, DropNewest)
+	s.AddAll(1, 2, 3)
+	ch := s.Watch(ctx)
+
+	popped := NewThreadUnsafeSet()
+	popped.Add(s.Pop())
+	popped.AddAll(s.PopN(2)...)
+	if s.Pop() != nil {
+		t.Error("Pop on an empty set should return nil")
+	}
+
+	removed := NewThreadUnsafeSet()
+	for _, ev := range collect(ch, 3) {
+		if ev.Type != Removed {
+			t.Errorf("expected a Removed event, got %v", ev)
+		}
+		removed.Add(ev.Elem)
+	}
+	if !removed.Equal(popped) {
+		t.Errorf("expected Removed events for %v, got %v", popped.ToSlice(), removed.ToSlice())
+	}
+}
+
+func Test_ObservableSubscribe(t *testing.T) {
+	s := Observe(nil, N, DropNewest)
+
+	var mu sync.Mutex
+	var events []Event
+	done := make(chan struct{})
+	cancel := s.Subscribe(func(ev Event) {
+		mu.Lock()
+		events = append(events, ev)
+		if len(events) == N {
+			close(done)
+		}
+		mu.Unlock()
+	})
+	defer cancel()
+
+	for i := 0; i < N; i++ {
+		s.Add(i)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the subscriber should receive every event")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, ev := range events {
+		if ev.Seq != uint64(i+1) || ev.Elem != i {
+			t.Errorf("events should be delivered in order, got %v at %d", ev, i)
+			break
+		}
+	}
+}
+
+func Test_ObservableOverflow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, tc := range []struct {
+		policy OverflowPolicy
+		first  uint64
+	}{
+		{DropNewest, 1},
+		{DropOldest, 8},
+	} {
+		s := Observe(NewMapSet(), 3, tc.policy)
+		ch := s.Watch(ctx)
+
+		// 没有人读取时写者也不会被阻塞
+		for i := 0; i < 10; i++ {
+			s.Add(i)
+		}
+		events := collect(ch, 3)
+		if len(events) != 3 || events[0].Seq != tc.first || events[2].Seq != tc.first+2 {
+			t.Errorf("policy %d: unexpected events %v", tc.policy, events)
+		}
+	}
+}
+
+func Test_ObservableWatchCancel(t *testing.T) {
+	s := Observe(NewMapSet(), 0, DropNewest)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := s.Watch(ctx)
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("no events should be sent")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancelling the context should close the channel")
+	}
+	s.Add(1)
+}
+
+func Test_ObservableBulkOps(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := Observe(NewMapSet(1, 2, 3, 4), 0, DropNewest)
+	ch := s.Watch(ctx)
+
+	s.IntersectWith(NewMapSet(1, 2, 3))
+	s.SymmetricDifferenceWith(NewMapSet(3, 5))
+	s.RemoveIf(func(elem interface{}) bool { return elem == 1 })
+	s.EachMutable(func(elem interface{}, set MapSet) bool {
+		set.Remove(elem)
+		set.Add(elem.(int) * 10)
+		return false
+	})
+
+	expected := []struct {
+		typ  EventType
+		elem interface{}
+	}{
+		{Removed, 4},
+		{Removed, 3},
+		{Added, 5},
+		{Removed, 1},
+	}
+	events := collect(ch, len(expected)+4)
+	if len(events) != len(expected)+4 {
+		t.Fatalf("expected %d events, got %v", len(expected)+4, events)
+	}
+	for i, e := range expected {
+		if events[i].Type != e.typ || events[i].Elem != e.elem {
+			t.Errorf("event %d: expected %v %v, got %v", i, e.typ, e.elem, events[i])
+		}
+	}
+
+	changes := NewThreadUnsafeSet()
+	for _, ev := range events[len(expected):] {
+		changes.Add(fmt.Sprintf("%v:%v", ev.Type, ev.Elem))
+	}
+	if !changes.Equal(NewThreadUnsafeSetFromSlice([]interface{}{"Removed:2", "Removed:5", "Added:20", "Added:50"})) {
+		t.Errorf("unexpected EachMutable events %v", changes.ToSlice())
+	}
+}
@@ -0,0 +1,31 @@
+package mapSet
+
+// PopOrder决定有序集合Pop和PopN弹出元素的顺序
+type PopOrder int
+
+const (
+	// FIFO先进先出,弹出最早加入的元素
+	FIFO PopOrder = iota
+	// LIFO后进先出,弹出最后加入的元素
+	LIFO
+)
+
+// NewOrderedSet创建并返回一个线程安全的有序MapSet。
+// 有序MapSet记住元素的加入顺序,Each,ToSlice,String和JSON序列化都按加入顺序进行,
+// Pop和PopN按order指定的顺序弹出,Add,Remove,Contains仍为O(1)
+func NewOrderedSet(order PopOrder, s ...interface{}) MapSet {
+	set := newThreadSafeOrderedSet(order)
+	for _, value := range s {
+		set.Add(value)
+	}
+	return &set
+}
+
+// NewThreadUnsafeOrderedSet创建并返回一个线程不安全的有序MapSet
+func NewThreadUnsafeOrderedSet(order PopOrder, s ...interface{}) MapSet {
+	set := newThreadUnsafeOrderedSet(order)
+	for _, value := range s {
+		set.Add(value)
+	}
+	return &set
+}
@@ -0,0 +1,175 @@
+package mapSet
+
+import (
+	"encoding/json"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func makeOrderedSets(order PopOrder, elems ...interface{}) []MapSet {
+	return []MapSet{NewOrderedSet(order, elems...), NewThreadUnsafeOrderedSet(order, elems...)}
+}
+
+func assertSliceEqual(a, b []interface{}, t *testing.T) {
+	t.Helper()
+	if len(a) != len(b) {
+		t.Errorf("%v != %v", a, b)
+		return
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("%v != %v", a, b)
+			return
+		}
+	}
+}
+
+func Test_OrderedSetInsertionOrder(t *testing.T) {
+	for _, s := range makeOrderedSets(FIFO, "c", "a", "b", "a") {
+		if s.RetElementCount() != 3 {
+			t.Errorf("%T should have 3 elements since a is a duplicate", s)
+		}
+		assertSliceEqual(s.ToSlice(), []interface{}{"c", "a", "b"}, t)
+
+		if s.String(",") != "Set{c,a,b}" {
+			t.Errorf("%T: unexpected string %s", s, s.String(","))
+		}
+
+		var each []interface{}
+		s.Each(func(elem interface{}) bool {
+			each = append(each, elem)
+			return false
+		})
+		assertSliceEqual(each, []interface{}{"c", "a", "b"}, t)
+
+		s.Remove("a")
+		s.Add("a")
+		assertSliceEqual(s.ToSlice(), []interface{}{"c", "b", "a"}, t)
+	}
+}
+
+func Test_OrderedSetEachWhileRemoving(t *testing.T) {
+	s := NewThreadUnsafeOrderedSet(FIFO, 1, 2, 3, 4)
+
+	var visited []interface{}
+	s.Each(func(elem interface{}) bool {
+		visited = append(visited, elem)
+		if elem == 1 {
+			s.Remove(2)
+		}
+		if elem == 3 {
+			// 删除当前元素和下一个元素后重新加入,新加入的元素不会被遍历
+			s.Remove(3)
+			s.Remove(4)
+			s.Add(4)
+		}
+		return false
+	})
+	assertSliceEqual(visited, []interface{}{1, 3}, t)
+	assertSliceEqual(s.ToSlice(), []interface{}{1, 4}, t)
+}
+
+func Test_OrderedSetPop(t *testing.T) {
+	for _, s := range makeOrderedSets(FIFO, 1, 2, 3, 4) {
+		if s.Pop() != 1 || s.Pop() != 2 {
+			t.Errorf("%T: FIFO Pop should return the oldest elements first", s)
+		}
+		assertSliceEqual(s.PopN(5), []interface{}{3, 4}, t)
+		if s.Pop() != nil {
+			t.Errorf("%T: Pop on an empty set should return nil", s)
+		}
+	}
+
+	for _, s := range makeOrderedSets(LIFO, 1, 2, 3, 4) {
+		if s.Pop() != 4 || s.Pop() != 3 {
+			t.Errorf("%T: LIFO Pop should return the newest elements first", s)
+		}
+		assertSliceEqual(s.PopN(1), []interface{}{2}, t)
+	}
+}
+
+func Test_OrderedSetEqual(t *testing.T) {
+	a := NewOrderedSet(FIFO, 1, 2, 3)
+	b := NewThreadUnsafeOrderedSet(LIFO, 3, 2, 1)
+
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Error("ordered sets with the same elements should be equal regardless of order")
+	}
+	if !a.Equal(makeSet([]int{1, 2, 3})) || !makeUnsafeSet([]int{1, 2, 3}).Equal(b) {
+		t.Error("ordered sets should be comparable with the other implementations")
+	}
+}
+
+func Test_OrderedSetAlgebra(t *testing.T) {
+	for _, a := range makeOrderedSets(FIFO, 3, 1, 2) {
+		b := NewOrderedSet(FIFO, 5, 2, 4, 3)
+
+		assertSliceEqual(a.Union(b).ToSlice(), []interface{}{3, 1, 2, 5, 4}, t)
+		assertSliceEqual(a.Intersect(b).ToSlice(), []interface{}{3, 2}, t)
+		assertSliceEqual(a.Difference(b).ToSlice(), []interface{}{1}, t)
+		assertSliceEqual(a.SymmetricDifference(b).ToSlice(), []interface{}{1, 5, 4}, t)
+
+		if !a.Intersect(b).IsProperSubset(a) || !a.IsSuperset(a.Difference(b)) {
+			t.Errorf("%T: subset predicates returned the wrong result", a)
+		}
+		if a.IsDisjoint(b) || !a.IsDisjoint(makeSet([]int{9})) {
+			t.Errorf("%T: IsDisjoint returned the wrong result", a)
+		}
+	}
+}
+
+func Test_OrderedSetClone(t *testing.T) {
+	for _, a := range makeOrderedSets(LIFO, "x", "y", "z") {
+		b := a.Clone()
+		assertSliceEqual(b.ToSlice(), []interface{}{"x", "y", "z"}, t)
+
+		b.Remove("z")
+		if a.Equal(b) {
+			t.Errorf("%T: modifying the clone should not affect the original", a)
+		}
+		if b.Pop() != "y" {
+			t.Errorf("%T: the clone should keep the pop order", a)
+		}
+	}
+}
+
+func Test_OrderedSetJSON(t *testing.T) {
+	for _, a := range makeOrderedSets(FIFO, "b", "c", "a") {
+		b, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `["b","c","a"]` {
+			t.Errorf("%T: unexpected encoding %s", a, b)
+		}
+	}
+
+	for _, s := range makeOrderedSets(FIFO) {
+		if err := json.Unmarshal([]byte(`["z", "y", [1], "x"]`), s); err != nil {
+			t.Fatal(err)
+		}
+		assertSliceEqual(s.ToSlice(), []interface{}{"z", "y", "x"}, t)
+	}
+}
+
+func Test_OrderedSetAddConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewOrderedSet(FIFO)
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for i := 0; i < N; i++ {
+		go func(i int) {
+			s.Add(i)
+			s.Union(s)
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	if s.RetElementCount() != N || len(s.ToSlice()) != N {
+		t.Errorf("expected %d elements, got %d", N, s.RetElementCount())
+	}
+}
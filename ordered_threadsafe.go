@@ -0,0 +1,346 @@
+package mapSet
+
+import (
+	"iter"
+	"sync"
+)
+
+type threadSafeOrderedSet struct {
+	s threadUnsafeOrderedSet
+	sync.RWMutex
+}
+
+func newThreadSafeOrderedSet(pop PopOrder) threadSafeOrderedSet {
+	return threadSafeOrderedSet{s: newThreadUnsafeOrderedSet(pop)}
+}
+
+// rlockOther与threadSafeSet.rlockOther相同,给调用者加读锁并返回锁内可以直接访问的other。
+// 对其他带锁实现取的快照保持other的遍历顺序
+func (set *threadSafeOrderedSet) rlockOther(other ReadOnlySet) (ReadOnlySet, func()) {
+	other = orEmpty(other)
+	if o, ok := other.(*threadSafeOrderedSet); ok && o != nil {
+		rlockPair(set, o)
+		return &o.s, func() { runlockPair(set, o) }
+	}
+	if isThreadUnsafe(other) {
+		set.RLock()
+		return other, set.RUnlock
+	}
+	snapshot := NewThreadUnsafeOrderedSet(FIFO, other.ToSlice()...)
+	set.RLock()
+	return snapshot, set.RUnlock
+}
+
+func (set *threadSafeOrderedSet) Add(i interface{}) bool {
+	set.Lock()
+	ret := set.s.Add(i)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) Contains(i ...interface{}) bool {
+	set.RLock()
+	ret := set.s.Contains(i...)
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) Clear() {
+	set.Lock()
+	set.s.Clear()
+	set.Unlock()
+}
+
+func (set *threadSafeOrderedSet) setRandSource(r *randSource) {
+	set.Lock()
+	set.s.setRandSource(r)
+	set.Unlock()
+}
+
+func (set *threadSafeOrderedSet) Remove(i interface{}) {
+	set.Lock()
+	set.s.Remove(i)
+	set.Unlock()
+}
+
+func (set *threadSafeOrderedSet) Delete(i interface{}) bool {
+	set.Lock()
+	ret := set.s.Delete(i)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) AddAll(items ...interface{}) int {
+	set.Lock()
+	ret := set.s.AddAll(items...)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) RemoveAll(items ...interface{}) int {
+	set.Lock()
+	ret := set.s.RemoveAll(items...)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) ContainsAny(items ...interface{}) bool {
+	set.RLock()
+	ret := set.s.ContainsAny(items...)
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) RetElementCount() int {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.RetElementCount()
+}
+
+func (set *threadSafeOrderedSet) Each(cb func(interface{}) bool) {
+	set.RLock()
+	set.s.Each(cb)
+	set.RUnlock()
+}
+
+func (set *threadSafeOrderedSet) RemoveIf(pred func(interface{}) bool) int {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.RemoveIf(pred)
+}
+
+func (set *threadSafeOrderedSet) RetainIf(pred func(interface{}) bool) int {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.RetainIf(pred)
+}
+
+func (set *threadSafeOrderedSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	set.Lock()
+	defer set.Unlock()
+	set.s.EachMutable(cb)
+}
+
+// lockOther与rlockOther相同,但给调用者加写锁,用于原地修改调用者的操作。
+// other就是调用者自身时只加一次写锁
+func (set *threadSafeOrderedSet) lockOther(other ReadOnlySet) (ReadOnlySet, func()) {
+	other = orEmpty(other)
+	if o, ok := other.(*threadSafeOrderedSet); ok && o != nil {
+		if o == set {
+			set.Lock()
+			return &set.s, set.Unlock
+		}
+		lockWithReader(set, o)
+		return &o.s, func() { unlockWithReader(set, o) }
+	}
+	if isThreadUnsafe(other) {
+		set.Lock()
+		return other, set.Unlock
+	}
+	snapshot := NewThreadUnsafeOrderedSet(FIFO, other.ToSlice()...)
+	set.Lock()
+	return snapshot, set.Unlock
+}
+
+func (set *threadSafeOrderedSet) Equal(other ReadOnlySet) bool {
+	if set == nil {
+		return isNilSet(other) || other.RetElementCount() == 0
+	}
+
+	o, unlock := set.rlockOther(other)
+	ret := set.s.Equal(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) Union(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeUnion := set.s.Union(o).(*threadUnsafeOrderedSet)
+	ret := &threadSafeOrderedSet{s: *unsafeUnion}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) Intersect(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeIntersection := set.s.Intersect(o).(*threadUnsafeOrderedSet)
+	ret := &threadSafeOrderedSet{s: *unsafeIntersection}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) Difference(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeDifference := set.s.Difference(o).(*threadUnsafeOrderedSet)
+	ret := &threadSafeOrderedSet{s: *unsafeDifference}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) SymmetricDifference(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeDifference := set.s.SymmetricDifference(o).(*threadUnsafeOrderedSet)
+	ret := &threadSafeOrderedSet{s: *unsafeDifference}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) UnionWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.UnionWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) IntersectWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.IntersectWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) DifferenceWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.DifferenceWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.SymmetricDifferenceWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) IsSubset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsSubset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) IsProperSubset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsProperSubset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) IsSuperset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsSuperset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) IsProperSuperset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsProperSuperset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) IsDisjoint(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsDisjoint(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) Clone() MapSet {
+	set.RLock()
+
+	unsafeClone := set.s.Clone().(*threadUnsafeOrderedSet)
+	ret := &threadSafeOrderedSet{s: *unsafeClone}
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) Pop() interface{} {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.Pop()
+}
+
+func (set *threadSafeOrderedSet) PopN(k int) []interface{} {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.PopN(k)
+}
+
+func (set *threadSafeOrderedSet) String(sep string) string {
+	set.RLock()
+	ret := set.s.String(sep)
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) RandomReturn() interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.RandomReturn()
+}
+
+func (set *threadSafeOrderedSet) Sample(k int) []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Sample(k)
+}
+
+func (set *threadSafeOrderedSet) SampleWithReplacement(k int) []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.SampleWithReplacement(k)
+}
+
+func (set *threadSafeOrderedSet) Shuffled() []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Shuffled()
+}
+
+func (set *threadSafeOrderedSet) ToSlice() []interface{} {
+	set.RLock()
+	ret := set.s.ToSlice()
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeOrderedSet) Freeze() FrozenSet {
+	return newFrozenSet(set.ToSlice())
+}
+
+func (set *threadSafeOrderedSet) Iterator() *Iterator {
+	return newIterator(set.ToSlice())
+}
+
+func (set *threadSafeOrderedSet) All() iter.Seq[interface{}] {
+	return snapshotSeq(set.ToSlice)
+}
+
+// MarshalJSON在读锁下把集合按加入顺序编码为JSON数组
+func (set *threadSafeOrderedSet) MarshalJSON() ([]byte, error) {
+	set.RLock()
+	b, err := set.s.MarshalJSON()
+	set.RUnlock()
+	return b, err
+}
+
+// UnmarshalJSON先在锁外解码JSON数组,再在写锁下按数组顺序把元素加入集合
+func (set *threadSafeOrderedSet) UnmarshalJSON(b []byte) error {
+	elems, err := unmarshalJSONPrimitives(b)
+	if err != nil {
+		return err
+	}
+
+	set.Lock()
+	if set.s.items == nil {
+		set.s.Clear()
+	}
+	for _, elem := range elems {
+		set.s.Add(elem)
+	}
+	set.Unlock()
+	return nil
+}
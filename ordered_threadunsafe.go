@@ -0,0 +1,473 @@
+package mapSet
+
+import (
+	"container/list"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// threadUnsafeOrderedSet用map保存元素到链表节点的索引,链表保存加入顺序
+type threadUnsafeOrderedSet struct {
+	items map[interface{}]*list.Element
+	order *list.List
+	pop   PopOrder
+	// rnd是SetRandSource设置的随机数源,Clear和Clone都保留它
+	rnd *randSource
+}
+
+func newThreadUnsafeOrderedSet(pop PopOrder) threadUnsafeOrderedSet {
+	return threadUnsafeOrderedSet{
+		items: make(map[interface{}]*list.Element),
+		order: list.New(),
+		pop:   pop,
+	}
+}
+
+func (set *threadUnsafeOrderedSet) Add(i interface{}) bool {
+	if _, found := set.items[i]; found {
+		return false //False if it existed already
+	}
+
+	set.items[i] = set.order.PushBack(i)
+	return true
+}
+
+func (set *threadUnsafeOrderedSet) Contains(i ...interface{}) bool {
+	for _, val := range i {
+		if _, ok := set.items[val]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *threadUnsafeOrderedSet) Clear() {
+	set.items = make(map[interface{}]*list.Element)
+	set.order = list.New()
+}
+
+func (set *threadUnsafeOrderedSet) setRandSource(r *randSource) {
+	set.rnd = r
+}
+
+func (set *threadUnsafeOrderedSet) Remove(i interface{}) {
+	if e, ok := set.items[i]; ok {
+		set.order.Remove(e)
+		delete(set.items, i)
+	}
+}
+
+func (set *threadUnsafeOrderedSet) Delete(i interface{}) bool {
+	e, ok := set.items[i]
+	if !ok {
+		return false
+	}
+	set.order.Remove(e)
+	delete(set.items, i)
+	return true
+}
+
+func (set *threadUnsafeOrderedSet) AddAll(items ...interface{}) int {
+	added := 0
+	for _, item := range items {
+		if set.Add(item) {
+			added++
+		}
+	}
+	return added
+}
+
+func (set *threadUnsafeOrderedSet) RemoveAll(items ...interface{}) int {
+	removed := 0
+	for _, item := range items {
+		if set.Delete(item) {
+			removed++
+		}
+	}
+	return removed
+}
+
+func (set *threadUnsafeOrderedSet) ContainsAny(items ...interface{}) bool {
+	for _, item := range items {
+		if set.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *threadUnsafeOrderedSet) RetElementCount() int {
+	return len(set.items)
+}
+
+// Each遍历开始时的链表节点快照:回调中删除的元素如果尚未遍历到会被跳过,
+// 新加入的元素不会被遍历,因此回调中增删任何元素都不会中断遍历
+func (set *threadUnsafeOrderedSet) Each(cb func(interface{}) bool) {
+	nodes := make([]*list.Element, 0, set.order.Len())
+	for e := set.order.Front(); e != nil; e = e.Next() {
+		nodes = append(nodes, e)
+	}
+	for _, e := range nodes {
+		if set.items[e.Value] != e {
+			continue
+		}
+		if cb(e.Value) {
+			break
+		}
+	}
+}
+
+func (set *threadUnsafeOrderedSet) RemoveIf(pred func(interface{}) bool) int {
+	removed := 0
+	for e := set.order.Front(); e != nil; {
+		next := e.Next()
+		if pred(e.Value) {
+			set.order.Remove(e)
+			delete(set.items, e.Value)
+			removed++
+		}
+		e = next
+	}
+	return removed
+}
+
+func (set *threadUnsafeOrderedSet) RetainIf(pred func(interface{}) bool) int {
+	return set.RemoveIf(func(elem interface{}) bool {
+		return !pred(elem)
+	})
+}
+
+func (set *threadUnsafeOrderedSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	eachMutable(set, cb)
+}
+
+// popElement返回按弹出顺序下一个要弹出的链表节点
+func (set *threadUnsafeOrderedSet) popElement() *list.Element {
+	if set.pop == LIFO {
+		return set.order.Back()
+	}
+	return set.order.Front()
+}
+
+func (set *threadUnsafeOrderedSet) Pop() interface{} {
+	e := set.popElement()
+	if e == nil {
+		return nil
+	}
+	set.order.Remove(e)
+	delete(set.items, e.Value)
+	return e.Value
+}
+
+func (set *threadUnsafeOrderedSet) PopN(k int) []interface{} {
+	if k > len(set.items) {
+		k = len(set.items)
+	}
+	if k < 0 {
+		k = 0
+	}
+	items := make([]interface{}, 0, k)
+	for len(items) < k {
+		items = append(items, set.Pop())
+	}
+	return items
+}
+
+func (set *threadUnsafeOrderedSet) Equal(other ReadOnlySet) bool {
+	if set == nil {
+		return isNilSet(other) || other.RetElementCount() == 0
+	}
+	other = orEmpty(other)
+
+	if set.RetElementCount() != other.RetElementCount() {
+		return false
+	}
+	for elem := range set.items {
+		if !other.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// emptyLike返回一个与set弹出顺序相同的空有序集合
+func (set *threadUnsafeOrderedSet) emptyLike() threadUnsafeOrderedSet {
+	return newThreadUnsafeOrderedSet(set.pop)
+}
+
+func (set *threadUnsafeOrderedSet) Union(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	unionedSet := set.emptyLike()
+	set.Each(func(elem interface{}) bool {
+		unionedSet.Add(elem)
+		return false
+	})
+	other.Each(func(elem interface{}) bool {
+		unionedSet.Add(elem)
+		return false
+	})
+	return &unionedSet
+}
+
+func (set *threadUnsafeOrderedSet) Intersect(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	// 结果保持调用者中的顺序
+	intersection := set.emptyLike()
+	set.Each(func(elem interface{}) bool {
+		if other.Contains(elem) {
+			intersection.Add(elem)
+		}
+		return false
+	})
+	return &intersection
+}
+
+func (set *threadUnsafeOrderedSet) Difference(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	difference := set.emptyLike()
+	set.Each(func(elem interface{}) bool {
+		if !other.Contains(elem) {
+			difference.Add(elem)
+		}
+		return false
+	})
+	return &difference
+}
+
+func (set *threadUnsafeOrderedSet) SymmetricDifference(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	sd := set.emptyLike()
+	set.Each(func(elem interface{}) bool {
+		if !other.Contains(elem) {
+			sd.Add(elem)
+		}
+		return false
+	})
+	other.Each(func(elem interface{}) bool {
+		if !set.Contains(elem) {
+			sd.Add(elem)
+		}
+		return false
+	})
+	return &sd
+}
+
+func (set *threadUnsafeOrderedSet) UnionWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		return 0
+	}
+
+	added := 0
+	other.Each(func(elem interface{}) bool {
+		if set.Add(elem) {
+			added++
+		}
+		return false
+	})
+	return added
+}
+
+func (set *threadUnsafeOrderedSet) IntersectWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		return 0
+	}
+
+	return set.RemoveIf(func(elem interface{}) bool {
+		return !other.Contains(elem)
+	})
+}
+
+func (set *threadUnsafeOrderedSet) DifferenceWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		removed := set.RetElementCount()
+		set.Clear()
+		return removed
+	}
+
+	return set.RemoveIf(func(elem interface{}) bool {
+		return other.Contains(elem)
+	})
+}
+
+func (set *threadUnsafeOrderedSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		return set.DifferenceWith(other)
+	}
+
+	changed := 0
+	other.Each(func(elem interface{}) bool {
+		if !set.Delete(elem) {
+			set.Add(elem)
+		}
+		changed++
+		return false
+	})
+	return changed
+}
+
+func (set *threadUnsafeOrderedSet) IsSubset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if set.RetElementCount() > other.RetElementCount() {
+		return false
+	}
+	for elem := range set.items {
+		if !other.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *threadUnsafeOrderedSet) IsProperSubset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	return set.RetElementCount() < other.RetElementCount() && set.IsSubset(other)
+}
+
+func (set *threadUnsafeOrderedSet) IsSuperset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if set.RetElementCount() < other.RetElementCount() {
+		return false
+	}
+	ret := true
+	other.Each(func(elem interface{}) bool {
+		if !set.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+func (set *threadUnsafeOrderedSet) IsProperSuperset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	return set.RetElementCount() > other.RetElementCount() && set.IsSuperset(other)
+}
+
+func (set *threadUnsafeOrderedSet) IsDisjoint(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if set.RetElementCount() <= other.RetElementCount() {
+		for elem := range set.items {
+			if other.Contains(elem) {
+				return false
+			}
+		}
+		return true
+	}
+	ret := true
+	other.Each(func(elem interface{}) bool {
+		if set.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+func (set *threadUnsafeOrderedSet) Clone() MapSet {
+	clonedSet := set.emptyLike()
+	clonedSet.rnd = set.rnd
+	set.Each(func(elem interface{}) bool {
+		clonedSet.Add(elem)
+		return false
+	})
+	return &clonedSet
+}
+
+func (set *threadUnsafeOrderedSet) String(sep string) string {
+	items := make([]string, 0, len(set.items))
+
+	set.Each(func(elem interface{}) bool {
+		items = append(items, fmt.Sprintf("%v", elem))
+		return false
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, sep))
+}
+
+// RandomReturn按随机下标从链表较近的一端走到对应的元素,不需要计算元素的哈希
+func (set *threadUnsafeOrderedSet) RandomReturn() interface{} {
+	n := set.order.Len()
+	if n == 0 {
+		return nil
+	}
+	idx := set.rnd.intn(n)
+	if idx < n/2 {
+		e := set.order.Front()
+		for ; idx > 0; idx-- {
+			e = e.Next()
+		}
+		return e.Value
+	}
+	e := set.order.Back()
+	for idx = n - 1 - idx; idx > 0; idx-- {
+		e = e.Prev()
+	}
+	return e.Value
+}
+
+func (set *threadUnsafeOrderedSet) Sample(k int) []interface{} {
+	return randomElems(set.rnd, set.ToSlice(), k)
+}
+
+func (set *threadUnsafeOrderedSet) SampleWithReplacement(k int) []interface{} {
+	return randomElemsWithReplacement(set.rnd, set.ToSlice(), k)
+}
+
+func (set *threadUnsafeOrderedSet) Shuffled() []interface{} {
+	return randomElems(set.rnd, set.ToSlice(), set.order.Len())
+}
+
+func (set *threadUnsafeOrderedSet) ToSlice() []interface{} {
+	keys := make([]interface{}, 0, set.RetElementCount())
+	set.Each(func(elem interface{}) bool {
+		keys = append(keys, elem)
+		return false
+	})
+
+	return keys
+}
+
+func (set *threadUnsafeOrderedSet) Freeze() FrozenSet {
+	return newFrozenSet(set.ToSlice())
+}
+
+func (set *threadUnsafeOrderedSet) Iterator() *Iterator {
+	return newIterator(set.ToSlice())
+}
+
+func (set *threadUnsafeOrderedSet) All() iter.Seq[interface{}] {
+	return snapshotSeq(set.ToSlice)
+}
+
+// MarshalJSON creates a JSON array from the set in insertion order
+func (set *threadUnsafeOrderedSet) MarshalJSON() ([]byte, error) {
+	return marshalJSONElems(set.ToSlice())
+}
+
+// UnmarshalJSON appends the primitive elements of a JSON array to the set
+// in array order. Numbers are decoded as json.Number.
+func (set *threadUnsafeOrderedSet) UnmarshalJSON(b []byte) error {
+	elems, err := unmarshalJSONPrimitives(b)
+	if err != nil {
+		return err
+	}
+
+	if set.items == nil {
+		set.Clear()
+	}
+	for _, elem := range elems {
+		set.Add(elem)
+	}
+
+	return nil
+}
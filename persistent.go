@@ -0,0 +1,374 @@
+package mapSet
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// 哈希数组映射前缀树(HAMT)的参数:每层用哈希值的6位选择64个槽位中的一个
+const (
+	hamtBits = 6
+	hamtMask = 1<<hamtBits - 1
+)
+
+// hamtNode是HAMT的内部节点,bitmap的第i位表示槽位i是否有内容,
+// slots按槽位顺序只保存有内容的槽位。节点一旦创建就不再修改,修改时复制路径上的节点
+type hamtNode struct {
+	bitmap uint64
+	slots  []hamtSlot
+}
+
+// hamtSlot要么是子节点,要么是叶子。叶子保存哈希值完全相同的所有元素
+type hamtSlot struct {
+	node  *hamtNode
+	hash  uint64
+	elems []interface{}
+}
+
+func bitCount(x uint64) int {
+	n := 0
+	for ; x != 0; x &= x - 1 {
+		n++
+	}
+	return n
+}
+
+// position返回槽位bit在slots中的下标
+func (n *hamtNode) position(bit uint64) int {
+	return bitCount(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode) contains(hash uint64, elem interface{}, shift uint) bool {
+	for n != nil {
+		bit := uint64(1) << ((hash >> shift) & hamtMask)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		slot := n.slots[n.position(bit)]
+		if slot.node == nil {
+			if slot.hash != hash {
+				return false
+			}
+			for _, e := range slot.elems {
+				if e == elem {
+					return true
+				}
+			}
+			return false
+		}
+		n = slot.node
+		shift += hamtBits
+	}
+	return false
+}
+
+// with返回加入elem后的新节点,added表示elem原先不存在
+func (n *hamtNode) with(hash uint64, elem interface{}, shift uint) (*hamtNode, bool) {
+	bit := uint64(1) << ((hash >> shift) & hamtMask)
+	pos := n.position(bit)
+
+	if n.bitmap&bit == 0 {
+		slots := make([]hamtSlot, len(n.slots)+1)
+		copy(slots, n.slots[:pos])
+		slots[pos] = hamtSlot{hash: hash, elems: []interface{}{elem}}
+		copy(slots[pos+1:], n.slots[pos:])
+		return &hamtNode{bitmap: n.bitmap | bit, slots: slots}, true
+	}
+
+	slot := n.slots[pos]
+	var replacement hamtSlot
+	switch {
+	case slot.node != nil:
+		child, added := slot.node.with(hash, elem, shift+hamtBits)
+		if !added {
+			return n, false
+		}
+		replacement = hamtSlot{node: child}
+	case slot.hash == hash:
+		for _, e := range slot.elems {
+			if e == elem {
+				return n, false
+			}
+		}
+		elems := make([]interface{}, len(slot.elems), len(slot.elems)+1)
+		copy(elems, slot.elems)
+		replacement = hamtSlot{hash: hash, elems: append(elems, elem)}
+	default:
+		// 两个不同的哈希落在同一槽位,下沉为子节点
+		child := (&hamtNode{}).withLeaf(slot, shift+hamtBits)
+		child, _ = child.with(hash, elem, shift+hamtBits)
+		replacement = hamtSlot{node: child}
+	}
+
+	slots := make([]hamtSlot, len(n.slots))
+	copy(slots, n.slots)
+	slots[pos] = replacement
+	return &hamtNode{bitmap: n.bitmap, slots: slots}, true
+}
+
+// withLeaf把一个已有的叶子整体放入节点
+func (n *hamtNode) withLeaf(leaf hamtSlot, shift uint) *hamtNode {
+	for _, elem := range leaf.elems {
+		n, _ = n.with(leaf.hash, elem, shift)
+	}
+	return n
+}
+
+// without返回删除elem后的新节点,节点变空时返回nil,removed表示elem原先存在
+func (n *hamtNode) without(hash uint64, elem interface{}, shift uint) (*hamtNode, bool) {
+	bit := uint64(1) << ((hash >> shift) & hamtMask)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	pos := n.position(bit)
+	slot := n.slots[pos]
+
+	var replacement *hamtSlot
+	if slot.node != nil {
+		child, removed := slot.node.without(hash, elem, shift+hamtBits)
+		if !removed {
+			return n, false
+		}
+		if child != nil {
+			r := hamtSlot{node: child}
+			// 只剩一个叶子的子节点上提,保持树的紧凑
+			if len(child.slots) == 1 && child.slots[0].node == nil {
+				r = child.slots[0]
+			}
+			replacement = &r
+		}
+	} else {
+		if slot.hash != hash {
+			return n, false
+		}
+		idx := -1
+		for i, e := range slot.elems {
+			if e == elem {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return n, false
+		}
+		if len(slot.elems) > 1 {
+			elems := make([]interface{}, 0, len(slot.elems)-1)
+			elems = append(elems, slot.elems[:idx]...)
+			elems = append(elems, slot.elems[idx+1:]...)
+			replacement = &hamtSlot{hash: hash, elems: elems}
+		}
+	}
+
+	if replacement != nil {
+		slots := make([]hamtSlot, len(n.slots))
+		copy(slots, n.slots)
+		slots[pos] = *replacement
+		return &hamtNode{bitmap: n.bitmap, slots: slots}, true
+	}
+	if len(n.slots) == 1 {
+		return nil, true
+	}
+	slots := make([]hamtSlot, 0, len(n.slots)-1)
+	slots = append(slots, n.slots[:pos]...)
+	slots = append(slots, n.slots[pos+1:]...)
+	return &hamtNode{bitmap: n.bitmap &^ bit, slots: slots}, true
+}
+
+func (n *hamtNode) each(cb func(interface{}) bool) bool {
+	if n == nil {
+		return false
+	}
+	for _, slot := range n.slots {
+		if slot.node != nil {
+			if slot.node.each(cb) {
+				return true
+			}
+			continue
+		}
+		for _, elem := range slot.elems {
+			if cb(elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PersistentSet是基于哈希数组映射前缀树(HAMT)的不可变持久化集合。
+// With和Without不修改原集合,而是返回与原集合共享大部分结构的新版本,时间复杂度为O(log n),
+// 因此把快照交给读者不需要像Clone那样复制所有元素。PersistentSet可以安全地在多个goroutine间共享。
+// 零值是一个可用的空集合
+type PersistentSet struct {
+	root *hamtNode
+	n    int
+}
+
+// NewPersistentSet用给定元素创建PersistentSet
+func NewPersistentSet(elems ...interface{}) PersistentSet {
+	var p PersistentSet
+	for _, elem := range elems {
+		p = p.With(elem)
+	}
+	return p
+}
+
+// ToPersistentSet用s当前的元素创建PersistentSet,nil视为空集
+func ToPersistentSet(s MapSet) PersistentSet {
+	var p PersistentSet
+	orEmpty(s).Each(func(elem interface{}) bool {
+		p = p.With(elem)
+		return false
+	})
+	return p
+}
+
+// With返回加入elem后的新版本,elem已存在时返回p本身
+func (p PersistentSet) With(elem interface{}) PersistentSet {
+	root := p.root
+	if root == nil {
+		root = &hamtNode{}
+	}
+	root, added := root.with(hashElem(elem), elem, 0)
+	if !added {
+		return p
+	}
+	return PersistentSet{root: root, n: p.n + 1}
+}
+
+// Without返回删除elem后的新版本,elem不存在时返回p本身
+func (p PersistentSet) Without(elem interface{}) PersistentSet {
+	if p.root == nil {
+		return p
+	}
+	root, removed := p.root.without(hashElem(elem), elem, 0)
+	if !removed {
+		return p
+	}
+	return PersistentSet{root: root, n: p.n - 1}
+}
+
+// ToMapSet把PersistentSet转换为一个新的非线程安全的MapSet
+func (p PersistentSet) ToMapSet() MapSet {
+	return NewThreadUnsafeSetFromSlice(p.ToSlice())
+}
+
+func (p PersistentSet) RetElementCount() int {
+	return p.n
+}
+
+func (p PersistentSet) Contains(i ...interface{}) bool {
+	for _, val := range i {
+		if !p.root.contains(hashElem(val), val, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p PersistentSet) ContainsAny(items ...interface{}) bool {
+	for _, item := range items {
+		if p.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p PersistentSet) Each(cb func(interface{}) bool) {
+	p.root.each(cb)
+}
+
+func (p PersistentSet) ToSlice() []interface{} {
+	keys := make([]interface{}, 0, p.n)
+	p.Each(func(elem interface{}) bool {
+		keys = append(keys, elem)
+		return false
+	})
+	return keys
+}
+
+func (p PersistentSet) String(sep string) string {
+	items := make([]string, 0, p.n)
+	p.Each(func(elem interface{}) bool {
+		items = append(items, fmt.Sprintf("%v", elem))
+		return false
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, sep))
+}
+
+func (p PersistentSet) Equal(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	return p.n == other.RetElementCount() && p.IsSubset(other)
+}
+
+func (p PersistentSet) IsSubset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if p.n > other.RetElementCount() {
+		return false
+	}
+	ret := true
+	p.Each(func(elem interface{}) bool {
+		if !other.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+func (p PersistentSet) IsProperSubset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	return p.n < other.RetElementCount() && p.IsSubset(other)
+}
+
+func (p PersistentSet) IsSuperset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if p.n < other.RetElementCount() {
+		return false
+	}
+	ret := true
+	other.Each(func(elem interface{}) bool {
+		if !p.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+func (p PersistentSet) IsProperSuperset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	return p.n > other.RetElementCount() && p.IsSuperset(other)
+}
+
+func (p PersistentSet) IsDisjoint(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	ret := true
+	p.Each(func(elem interface{}) bool {
+		if other.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+func (p PersistentSet) Freeze() FrozenSet {
+	return newFrozenSet(p.ToSlice())
+}
+
+func (p PersistentSet) Iterator() *Iterator {
+	return newIterator(p.ToSlice())
+}
+
+func (p PersistentSet) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		p.root.each(func(elem interface{}) bool {
+			return !yield(elem)
+		})
+	}
+}
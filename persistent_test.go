@@ -0,0 +1,156 @@
+package mapSet
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_PersistentSetWithWithout(t *testing.T) {
+	var empty PersistentSet
+	if empty.RetElementCount() != 0 || empty.Contains(1) {
+		t.Error("the zero value should be an empty set")
+	}
+
+	a := NewPersistentSet(1, 2, 3)
+	b := a.With(4)
+	c := b.Without(1)
+
+	if a.RetElementCount() != 3 || !a.Contains(1, 2, 3) || a.Contains(4) {
+		t.Errorf("With should not modify the old version, got %v", a.ToSlice())
+	}
+	if b.RetElementCount() != 4 || !b.Contains(1, 2, 3, 4) {
+		t.Errorf("unexpected new version %v", b.ToSlice())
+	}
+	if c.RetElementCount() != 3 || c.Contains(1) || !c.Contains(2, 3, 4) {
+		t.Errorf("unexpected new version %v", c.ToSlice())
+	}
+	if b.RetElementCount() != 4 || !b.Contains(1) {
+		t.Errorf("Without should not modify the old version, got %v", b.ToSlice())
+	}
+
+	if a.With(1).root != a.root || a.Without(5).root != a.root {
+		t.Error("adding an existing element or removing a missing one should return the same version")
+	}
+	if a.Without(1).Without(2).Without(3).RetElementCount() != 0 {
+		t.Error("removing every element should give an empty set")
+	}
+}
+
+func Test_PersistentSetMatchesMap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	expected := map[interface{}]struct{}{}
+	var p PersistentSet
+	var versions []PersistentSet
+	var counts []int
+
+	for i := 0; i < 5000; i++ {
+		v := r.Intn(2000)
+		if r.Intn(3) == 0 {
+			delete(expected, v)
+			p = p.Without(v)
+		} else {
+			expected[v] = struct{}{}
+			p = p.With(v)
+		}
+		if i%500 == 0 {
+			versions = append(versions, p)
+			counts = append(counts, len(expected))
+		}
+	}
+
+	if p.RetElementCount() != len(expected) {
+		t.Fatalf("expected %d elements, got %d", len(expected), p.RetElementCount())
+	}
+	for v := range expected {
+		if !p.Contains(v) {
+			t.Errorf("missing element %v", v)
+		}
+	}
+	if len(p.ToSlice()) != len(expected) {
+		t.Errorf("ToSlice returned %d elements, expected %d", len(p.ToSlice()), len(expected))
+	}
+	for i, v := range versions {
+		if v.RetElementCount() != counts[i] || len(v.ToSlice()) != counts[i] {
+			t.Errorf("old version %d changed: expected %d elements, got %d", i, counts[i], v.RetElementCount())
+		}
+	}
+}
+
+func Test_PersistentSetHashCollision(t *testing.T) {
+	// 直接构造哈希值完全相同的元素
+	root, _ := (&hamtNode{}).with(42, "a", 0)
+	root, _ = root.with(42, "b", 0)
+	root, _ = root.with(42|1<<hamtBits, "c", 0)
+
+	for _, elem := range []interface{}{"a", "b"} {
+		if !root.contains(42, elem, 0) {
+			t.Errorf("missing colliding element %v", elem)
+		}
+	}
+	if !root.contains(42|1<<hamtBits, "c", 0) || root.contains(42, "c", 0) {
+		t.Error("elements should be looked up by their own hash")
+	}
+
+	root, removed := root.without(42, "a", 0)
+	if !removed || root.contains(42, "a", 0) || !root.contains(42, "b", 0) {
+		t.Error("removing a colliding element should keep the others")
+	}
+	root, _ = root.without(42, "b", 0)
+	root, _ = root.without(42|1<<hamtBits, "c", 0)
+	if root != nil {
+		t.Error("removing every element should leave an empty trie")
+	}
+}
+
+func Test_PersistentSetConversion(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3) {
+		p := ToPersistentSet(s)
+		if !p.Equal(s) || !s.Equal(p.ToMapSet()) {
+			t.Errorf("%T: conversion changed the elements: %v", s, p.ToSlice())
+		}
+		s.Add(4)
+		if p.Contains(4) {
+			t.Errorf("%T: modifying the source should not affect the persistent set", s)
+		}
+	}
+
+	if ToPersistentSet(nil).RetElementCount() != 0 {
+		t.Error("a nil set should convert to an empty persistent set")
+	}
+	if NewPersistentSet(1, 2).Freeze() != NewMapSet(2, 1).Freeze() {
+		t.Error("frozen persistent sets should equal frozen sets with the same elements")
+	}
+}
+
+func Test_PersistentSetPredicates(t *testing.T) {
+	p := NewPersistentSet(1, 2, 3)
+
+	if !p.IsSubset(NewMapSet(1, 2, 3, 4)) || !p.IsProperSubset(NewMapSet(1, 2, 3, 4)) {
+		t.Error("p should be a proper subset")
+	}
+	if !p.IsSuperset(NewMapSet(1, 2)) || !p.IsProperSuperset(NewMapSet(1, 2)) || p.IsProperSuperset(NewMapSet(1, 2, 3)) {
+		t.Error("unexpected superset result")
+	}
+	if !p.IsDisjoint(NewMapSet(4, 5)) || p.IsDisjoint(NewMapSet(3)) {
+		t.Error("unexpected disjoint result")
+	}
+	if !p.ContainsAny(5, 3) || p.ContainsAny(5) {
+		t.Error("unexpected ContainsAny result")
+	}
+
+	sum := 0
+	for elem := range p.All() {
+		sum += elem.(int)
+		if sum >= 3 {
+			break
+		}
+	}
+	it := p.Iterator()
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if sum < 3 || n != 3 {
+		t.Errorf("unexpected iteration: sum %d, count %d", sum, n)
+	}
+}
@@ -0,0 +1,312 @@
+package mapSet
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Tuple是可以作为MapSet元素或map键的定长元组,用于表示笛卡尔积中的一项。
+// Go的切片不能比较,Tuple把元素保存为嵌套的可比较结构体,
+// 因此元素相同且顺序相同的两个Tuple用==比较相等。元素本身必须是可比较的
+type Tuple struct {
+	n    int
+	head interface{}
+	// tail为剩余元素组成的Tuple,最后一个元素的tail为nil
+	tail interface{}
+}
+
+// NewTuple按给定顺序创建Tuple
+func NewTuple(elems ...interface{}) Tuple {
+	var t Tuple
+	for i := len(elems) - 1; i >= 0; i-- {
+		if t.n == 0 {
+			t = Tuple{n: 1, head: elems[i]}
+		} else {
+			t = Tuple{n: t.n + 1, head: elems[i], tail: t}
+		}
+	}
+	return t
+}
+
+// Len返回元素个数
+func (t Tuple) Len() int {
+	return t.n
+}
+
+// At返回第i个元素(从0开始),i越界时panic
+func (t Tuple) At(i int) interface{} {
+	if i < 0 || i >= t.n {
+		panic(fmt.Sprintf("mapSet: Tuple index %d out of range [0:%d]", i, t.n))
+	}
+	for ; i > 0; i-- {
+		t = t.tail.(Tuple)
+	}
+	return t.head
+}
+
+// ToSlice按顺序把元素作为切片返回
+func (t Tuple) ToSlice() []interface{} {
+	elems := make([]interface{}, 0, t.n)
+	for t.n > 0 {
+		elems = append(elems, t.head)
+		if t.n == 1 {
+			break
+		}
+		t = t.tail.(Tuple)
+	}
+	return elems
+}
+
+func (t Tuple) String() string {
+	items := make([]string, 0, t.n)
+	for _, elem := range t.ToSlice() {
+		items = append(items, fmt.Sprintf("%v", elem))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(items, ", "))
+}
+
+// Subset是可以作为MapSet元素或map键的不可变子集,用于表示幂集中的一项。
+// 元素按确定的规范顺序保存,因此元素相同的两个Subset用==比较相等,与加入顺序无关
+type Subset struct {
+	elems Tuple
+}
+
+// NewSubset用给定元素创建Subset,重复的元素只保留一个
+func NewSubset(elems ...interface{}) Subset {
+	return Subset{NewTuple(canonicalOrder(NewThreadUnsafeSetFromSlice(elems).ToSlice())...)}
+}
+
+// Len返回元素个数
+func (s Subset) Len() int {
+	return s.elems.Len()
+}
+
+// Contains判断元素是否在Subset中
+func (s Subset) Contains(i interface{}) bool {
+	for _, elem := range s.elems.ToSlice() {
+		if elem == i {
+			return true
+		}
+	}
+	return false
+}
+
+// ToSlice按规范顺序把元素作为切片返回
+func (s Subset) ToSlice() []interface{} {
+	return s.elems.ToSlice()
+}
+
+// ToMapSet把Subset转换为一个线程不安全的MapSet
+func (s Subset) ToMapSet() MapSet {
+	return NewThreadUnsafeSetFromSlice(s.ToSlice())
+}
+
+func (s Subset) String() string {
+	items := make([]string, 0, s.Len())
+	for _, elem := range s.ToSlice() {
+		items = append(items, fmt.Sprintf("%v", elem))
+	}
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+// canonicalOrder把元素按compareElems的全序就地排序并返回。
+// 该顺序与==一致,因此相等的元素集合无论加入顺序如何都排成同一个序列
+func canonicalOrder(elems []interface{}) []interface{} {
+	sort.Slice(elems, func(i, j int) bool {
+		return compareElems(elems[i], elems[j]) < 0
+	})
+	return elems
+}
+
+// compareElems比较两个可比较的元素,a == b时返回0。
+// 先比较动态类型,类型相同时按值比较:数字按大小(-0与+0相等),字符串按字典序,
+// 数组与结构体逐个比较元素或字段,指针和chan按地址比较。
+// 不含指针的元素的顺序与进程无关,按地址比较的元素只在同一进程内顺序确定
+func compareElems(a, b interface{}) int {
+	return compareValues(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func compareValues(a, b reflect.Value) int {
+	if !a.IsValid() || !b.IsValid() {
+		return cmp.Compare(boolRank(a.IsValid()), boolRank(b.IsValid()))
+	}
+	if c := compareTypes(a.Type(), b.Type()); c != 0 {
+		return c
+	}
+	switch a.Kind() {
+	case reflect.Bool:
+		return cmp.Compare(boolRank(a.Bool()), boolRank(b.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareFloats(a.Float(), b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		ca, cb := a.Complex(), b.Complex()
+		if c := compareFloats(real(ca), real(cb)); c != 0 {
+			return c
+		}
+		return compareFloats(imag(ca), imag(cb))
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		return cmp.Compare(a.Pointer(), b.Pointer())
+	case reflect.Interface:
+		return compareValues(a.Elem(), b.Elem())
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if c := compareValues(a.Index(i), b.Index(i)); c != 0 {
+				return c
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if c := compareValues(a.Field(i), b.Field(i)); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// compareTypes先按类型名再按包路径比较类型,两者都相同的不同类型(例如函数内定义的同名类型)按类型描述的地址比较
+func compareTypes(a, b reflect.Type) int {
+	if a == b {
+		return 0
+	}
+	if c := strings.Compare(a.String(), b.String()); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.PkgPath(), b.PkgPath()); c != 0 {
+		return c
+	}
+	return cmp.Compare(reflect.ValueOf(a).Pointer(), reflect.ValueOf(b).Pointer())
+}
+
+// compareFloats与cmp.Compare相同,但-0与+0相等。NaN排在所有数字之前,NaN之间按位比较使顺序确定
+func compareFloats(a, b float64) int {
+	if a == b {
+		return 0
+	}
+	if aNaN, bNaN := a != a, b != b; aNaN || bNaN {
+		if aNaN && bNaN {
+			return cmp.Compare(math.Float64bits(a), math.Float64bits(b))
+		}
+		return cmp.Compare(boolRank(!aNaN), boolRank(!bNaN))
+	}
+	return cmp.Compare(a, b)
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// plainLike返回一个与s线程安全性相同的普通空MapSet,
+// 用于元素类型与s不同、不能沿用s的排序或顺序设置的结果
+func plainLike(s ReadOnlySet) MapSet {
+	if isThreadUnsafe(s) {
+		return NewThreadUnsafeSet()
+	}
+	return NewMapSet()
+}
+
+// PowerSet返回s的幂集,即由s的所有子集(Subset)组成的MapSet,包括空集和s本身。
+// 结果与s的线程安全性相同。幂集有2^n个元素,元素较多时请使用PowerSetSeq
+func PowerSet(s MapSet) MapSet {
+	ret := plainLike(s)
+	for subset := range PowerSetSeq(s) {
+		ret.Add(subset)
+	}
+	return ret
+}
+
+// PowerSetSeq按需逐个生成s的所有子集,同一时刻只保存一个子集,
+// 适合遍历元素较多的集合的幂集。遍历的是range开始时s的快照
+func PowerSetSeq(s MapSet) iter.Seq[Subset] {
+	return func(yield func(Subset) bool) {
+		elems := canonicalOrder(orEmpty(s).ToSlice())
+		// chosen是一个n位的二进制计数器,第i位表示是否选中elems[i]
+		chosen := make([]bool, len(elems))
+		picked := make([]interface{}, 0, len(elems))
+		for {
+			picked = picked[:0]
+			for i, ok := range chosen {
+				if ok {
+					picked = append(picked, elems[i])
+				}
+			}
+			if !yield(Subset{NewTuple(picked...)}) {
+				return
+			}
+
+			i := 0
+			for i < len(chosen) && chosen[i] {
+				chosen[i] = false
+				i++
+			}
+			if i == len(chosen) {
+				return
+			}
+			chosen[i] = true
+		}
+	}
+}
+
+// CartesianProduct返回s与others的笛卡尔积,即由所有(x0, x1, ...)元组(Tuple)组成的MapSet,
+// 其中x0来自s,x1来自others[0],依此类推。结果与s的线程安全性相同,nil视为空集
+func CartesianProduct(s MapSet, others ...MapSet) MapSet {
+	ret := plainLike(s)
+	for t := range CartesianProductSeq(s, others...) {
+		ret.Add(t)
+	}
+	return ret
+}
+
+// CartesianProductSeq按需逐个生成s与others的笛卡尔积中的元组,
+// 遍历的是range开始时各集合的快照
+func CartesianProductSeq(s MapSet, others ...MapSet) iter.Seq[Tuple] {
+	return func(yield func(Tuple) bool) {
+		pools := make([][]interface{}, 0, len(others)+1)
+		for _, set := range append([]MapSet{s}, others...) {
+			pool := orEmpty(set).ToSlice()
+			if len(pool) == 0 {
+				return
+			}
+			pools = append(pools, pool)
+		}
+
+		// indices像里程表一样从最后一位开始进位
+		indices := make([]int, len(pools))
+		current := make([]interface{}, len(pools))
+		for {
+			for i, idx := range indices {
+				current[i] = pools[i][idx]
+			}
+			if !yield(NewTuple(current...)) {
+				return
+			}
+
+			i := len(indices) - 1
+			for i >= 0 {
+				indices[i]++
+				if indices[i] < len(pools[i]) {
+					break
+				}
+				indices[i] = 0
+				i--
+			}
+			if i < 0 {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package mapSet
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_Tuple(t *testing.T) {
+	a := NewTuple(1, "x", 2.5)
+	b := NewTuple(1, "x", 2.5)
+
+	if a != b {
+		t.Error("tuples with the same elements in the same order should be equal")
+	}
+	if a == NewTuple("x", 1, 2.5) || a == NewTuple(1, "x") {
+		t.Error("tuples with a different order or length should not be equal")
+	}
+	if a.Len() != 3 || a.At(1) != "x" || a.String() != "(1, x, 2.5)" {
+		t.Errorf("unexpected tuple %v", a)
+	}
+	assertSliceEqual(a.ToSlice(), []interface{}{1, "x", 2.5}, t)
+	if NewTuple().Len() != 0 || len(NewTuple().ToSlice()) != 0 {
+		t.Error("the empty tuple should have no elements")
+	}
+
+	s := NewMapSet(a)
+	if !s.Contains(b) {
+		t.Error("a tuple should be usable as a set element")
+	}
+}
+
+func Test_Subset(t *testing.T) {
+	a := NewSubset(3, 1, 2)
+	b := NewSubset(2, 3, 1, 1)
+
+	if a != b {
+		t.Error("subsets with the same elements should be equal regardless of order")
+	}
+	if a.Len() != 3 || !a.Contains(2) || a.Contains(4) {
+		t.Errorf("unexpected subset %v", a)
+	}
+	assertEqual(a.ToMapSet(), makeSet([]int{1, 2, 3}), t)
+}
+
+func Test_SubsetCanonicalOrder(t *testing.T) {
+	type point struct {
+		x, y int
+		tag  interface{}
+	}
+	x, y := 1, 1
+	p1, p2 := &x, &y
+	if NewSubset(p1, p2) != NewSubset(p2, p1) {
+		t.Error("pointers with equal pointees should still have one canonical order")
+	}
+
+	elems := []interface{}{
+		"b", "a", 2, int64(2), 1.5, point{1, 2, "t"}, point{1, 2, 3}, point{0, 9, nil},
+		[2]int{1, 2}, [2]int{0, 3}, true, false, nil, p1, p2,
+	}
+	want := NewSubset(elems...)
+	for i := 0; i < 20; i++ {
+		shuffled := NewThreadUnsafeSetFromSlice(elems).Shuffled()
+		if got := NewSubset(shuffled...); got != want {
+			t.Fatalf("NewSubset(%v) = %v, want %v", shuffled, got, want)
+		}
+	}
+
+	if NewSubset(0.0, 1.0) != NewSubset(1.0, math.Copysign(0, -1)) {
+		t.Error("0.0 and -0.0 are == and should give equal subsets")
+	}
+}
+
+func Test_PowerSet(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3) {
+		p := PowerSet(s)
+		if p.RetElementCount() != 8 {
+			t.Fatalf("%T: the power set of 3 elements should have 8 subsets, got %d", s, p.RetElementCount())
+		}
+		for _, subset := range []Subset{NewSubset(), NewSubset(1), NewSubset(2, 3), NewSubset(3, 2, 1)} {
+			if !p.Contains(subset) {
+				t.Errorf("%T: the power set should contain %v", s, subset)
+			}
+		}
+	}
+
+	if PowerSet(NewMapSet()).RetElementCount() != 1 {
+		t.Error("the power set of the empty set should contain only the empty set")
+	}
+	if _, ok := PowerSet(makeUnsafeSet([]int{1})).(*threadUnsafeSet); !ok {
+		t.Error("the power set should keep the thread-safety of the source set")
+	}
+}
+
+func Test_PowerSetSeqLazy(t *testing.T) {
+	s := NewThreadUnsafeSet()
+	for i := 0; i < 30; i++ {
+		s.Add(i)
+	}
+
+	count := 0
+	for subset := range PowerSetSeq(s) {
+		if subset.Len() > 30 {
+			t.Fatal("a subset cannot be larger than the set")
+		}
+		count++
+		if count == 1000 {
+			break
+		}
+	}
+	if count != 1000 {
+		t.Errorf("expected to stop after 1000 subsets, got %d", count)
+	}
+}
+
+func Test_CartesianProduct(t *testing.T) {
+	a := makeSet([]int{1, 2})
+	b := NewThreadUnsafeSetFromSlice([]interface{}{"x", "y", "z"})
+
+	p := CartesianProduct(a, b)
+	if p.RetElementCount() != 6 {
+		t.Fatalf("the product should have 6 tuples, got %d", p.RetElementCount())
+	}
+	if !p.Contains(NewTuple(1, "x"), NewTuple(2, "z")) || p.Contains(NewTuple("x", 1)) {
+		t.Errorf("unexpected product %v", p.ToSlice())
+	}
+
+	three := CartesianProduct(a, b, NewMapSet(true, false))
+	if three.RetElementCount() != 12 || !three.Contains(NewTuple(2, "y", false)) {
+		t.Errorf("unexpected product %v", three.ToSlice())
+	}
+
+	if CartesianProduct(a, NewMapSet()).RetElementCount() != 0 || CartesianProduct(a, nil).RetElementCount() != 0 {
+		t.Error("the product with an empty set should be empty")
+	}
+	if CartesianProduct(a).RetElementCount() != 2 {
+		t.Error("the product of a single set should contain one 1-tuple per element")
+	}
+}
+
+func Test_CartesianProductSeqEarlyExit(t *testing.T) {
+	count := 0
+	for range CartesianProductSeq(makeSet([]int{1, 2, 3}), makeSet([]int{4, 5, 6})) {
+		count++
+		if count == 4 {
+			break
+		}
+	}
+	if count != 4 {
+		t.Errorf("expected to stop after 4 tuples, got %d", count)
+	}
+}
@@ -0,0 +1,120 @@
+package mapSet
+
+import (
+	"hash/maphash"
+	"math/rand/v2"
+	"sync"
+)
+
+// randSource是一个集合自己的随机数源,由SetRandSource设置。
+// *rand.Rand不是并发安全的,需要mu保护,但只有共用这个源的集合会互相等待。
+// nil表示未设置,这时使用math/rand/v2的顶层函数,它们无锁且并发安全
+type randSource struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// randSourceSetter由本包中支持设置随机数源的集合实现
+type randSourceSetter interface {
+	setRandSource(r *randSource)
+}
+
+// SetRandSource让集合s的RandomReturn,Pop,Sample,Shuffled等随机操作使用src,
+// s可以是本包创建的任意MapSet或Set[T],Clone得到的集合沿用同一个源。
+// 传入固定种子的源并按相同的顺序操作,可以让测试中的随机结果可复现。
+// src为nil时恢复默认的math/rand/v2顶层函数。s不支持设置随机数源时返回false
+func SetRandSource(s interface{}, src rand.Source) bool {
+	setter, ok := s.(randSourceSetter)
+	if !ok || isNilValue(s) {
+		return false
+	}
+	var r *randSource
+	if src != nil {
+		r = &randSource{rng: rand.New(src)}
+	}
+	setter.setRandSource(r)
+	return true
+}
+
+// SetRandSeed让集合s使用给定种子的随机数源,等价于SetRandSource(s, rand.NewPCG(seed, seed))
+func SetRandSeed(s interface{}, seed uint64) bool {
+	return SetRandSource(s, rand.NewPCG(seed, seed))
+}
+
+func (r *randSource) intn(n int) int {
+	if r == nil {
+		return rand.IntN(n)
+	}
+	r.mu.Lock()
+	ret := r.rng.IntN(n)
+	r.mu.Unlock()
+	return ret
+}
+
+func (r *randSource) perm(n int) []int {
+	if r == nil {
+		return rand.Perm(n)
+	}
+	r.mu.Lock()
+	ret := r.rng.Perm(n)
+	r.mu.Unlock()
+	return ret
+}
+
+// randomElems返回items的一个随机排列的前k个元素,k大于元素个数时返回全部元素。
+// 使用只交换前k个位置的Fisher-Yates洗牌,被交换的位置记录在map中,不复制items,时间复杂度为O(k)。
+// 随机数源的种子与items的顺序相同时结果可以复现
+func randomElems(r *randSource, items []interface{}, k int) []interface{} {
+	if k > len(items) {
+		k = len(items)
+	}
+	if k <= 0 {
+		return []interface{}{}
+	}
+
+	swapped := make(map[int]int, k)
+	at := func(i int) int {
+		if j, ok := swapped[i]; ok {
+			return j
+		}
+		return i
+	}
+	ret := make([]interface{}, k)
+	for i := range ret {
+		j := i + r.intn(len(items)-i)
+		ret[i] = items[at(j)]
+		swapped[j] = at(i)
+	}
+	return ret
+}
+
+// randomElemsWithReplacement有放回地从items中等概率抽取k个元素,结果中可能有重复元素
+func randomElemsWithReplacement(r *randSource, items []interface{}, k int) []interface{} {
+	if k <= 0 || len(items) == 0 {
+		return []interface{}{}
+	}
+
+	ret := make([]interface{}, k)
+	for i := range ret {
+		ret[i] = items[r.intn(len(items))]
+	}
+	return ret
+}
+
+// mix64是splitmix64的输出函数,把输入充分打散为均匀分布的64位整数
+func mix64(z uint64) uint64 {
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// hashSeed是hashElem使用的种子,在进程启动时随机生成
+var hashSeed = maphash.MakeSeed()
+
+// hashElem计算与==一致的元素哈希:相等的元素哈希一定相同。
+// 指针,channel按地址计算,+0.0与-0.0哈希相同,与map的键的行为一致。
+// 种子在进程启动时随机生成,因此同一个值在不同进程中的哈希不同
+func hashElem(v interface{}) uint64 {
+	return maphash.Comparable(hashSeed, v)
+}
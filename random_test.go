@@ -0,0 +1,242 @@
+package mapSet
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func Test_RandomReturnUniform(t *testing.T) {
+	const draws = 40000
+	for _, s := range []MapSet{makeSet([]int{1, 2, 3, 4}), makeUnsafeSet([]int{1, 2, 3, 4})} {
+		SetRandSeed(s, 1)
+		counts := make(map[interface{}]int)
+		for i := 0; i < draws; i++ {
+			counts[s.RandomReturn()]++
+		}
+
+		expected := draws / s.RetElementCount()
+		for elem, count := range counts {
+			// 标准差约为87,允许约7个标准差的偏差
+			if count < expected-600 || count > expected+600 {
+				t.Errorf("%T: element %v was returned %d times, expected about %d", s, elem, count, expected)
+			}
+		}
+		if len(counts) != s.RetElementCount() {
+			t.Errorf("%T: only %d distinct elements were returned", s, len(counts))
+		}
+	}
+}
+
+func Test_RandomReturnEmpty(t *testing.T) {
+	if NewMapSet().RandomReturn() != nil || NewThreadUnsafeSet().RandomReturn() != nil {
+		t.Error("RandomReturn on an empty set should return nil")
+	}
+	if v, ok := NewGenericSet[int]().RandomReturn(); ok || v != 0 {
+		t.Error("RandomReturn on an empty generic set should return the zero value and false")
+	}
+}
+
+func Test_PopReproducible(t *testing.T) {
+	drain := func(s MapSet) []interface{} {
+		var ret []interface{}
+		for s.RetElementCount() > 0 {
+			ret = append(ret, s.Pop())
+		}
+		return ret
+	}
+
+	a, b := makeSet([]int{1, 2, 3, 4, 5, 6, 7, 8}), makeUnsafeSet([]int{1, 2, 3, 4, 5, 6, 7, 8})
+	SetRandSeed(a, 42)
+	SetRandSeed(b, 42)
+	// 其他集合的随机操作不影响a和b的随机数源
+	NewMapSet(1, 2, 3).Pop()
+	first := drain(a)
+	second := drain(b)
+
+	if len(first) != 8 || len(second) != 8 {
+		t.Fatalf("expected 8 popped elements, got %v and %v", first, second)
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("pop order should be reproducible with the same seed: %v != %v", first, second)
+		}
+	}
+}
+
+func Test_GenericPopReproducible(t *testing.T) {
+	x, y := NewGenericSet("a", "b", "c", "d"), NewThreadUnsafeGenericSet("a", "b", "c", "d")
+	SetRandSeed(x, 7)
+	SetRandSeed(y, 7)
+	a, _ := x.Pop()
+	b, _ := y.Pop()
+
+	if a != b {
+		t.Errorf("pop should be reproducible with the same seed: %v != %v", a, b)
+	}
+}
+
+func Test_Sample(t *testing.T) {
+	for _, s := range []MapSet{makeSet([]int{1, 2, 3, 4, 5}), makeUnsafeSet([]int{1, 2, 3, 4, 5})} {
+		sample := s.Sample(3)
+		if len(sample) != 3 {
+			t.Fatalf("%T: expected 3 sampled elements, got %v", s, sample)
+		}
+		distinct := NewThreadUnsafeSetFromSlice(sample)
+		if distinct.RetElementCount() != 3 || !distinct.IsSubset(s) {
+			t.Errorf("%T: sample %v should be 3 distinct members of the set", s, sample)
+		}
+
+		if len(s.Sample(10)) != 5 || len(s.Sample(0)) != 0 || len(s.Sample(-1)) != 0 {
+			t.Errorf("%T: Sample should be clamped to [0, cardinality]", s)
+		}
+		if s.RetElementCount() != 5 {
+			t.Errorf("%T: Sample should not modify the set", s)
+		}
+	}
+}
+
+func Test_SampleUniform(t *testing.T) {
+	const draws = 30000
+	s := makeUnsafeSet([]int{1, 2, 3, 4, 5, 6})
+	SetRandSeed(s, 3)
+	counts := make(map[interface{}]int)
+	for i := 0; i < draws; i++ {
+		for _, elem := range s.Sample(2) {
+			counts[elem]++
+		}
+	}
+
+	// 每个元素被选中的概率为2/6
+	expected := draws * 2 / 6
+	for elem, count := range counts {
+		if count < expected-600 || count > expected+600 {
+			t.Errorf("element %v was sampled %d times, expected about %d", elem, count, expected)
+		}
+	}
+}
+
+func Test_SampleWithReplacement(t *testing.T) {
+	for _, s := range []MapSet{makeSet([]int{1, 2}), makeUnsafeSet([]int{1, 2})} {
+		sample := s.SampleWithReplacement(50)
+		if len(sample) != 50 {
+			t.Fatalf("%T: expected 50 sampled elements, got %d", s, len(sample))
+		}
+		if !s.Contains(sample...) {
+			t.Errorf("%T: sample %v contains elements outside of the set", s, sample)
+		}
+	}
+
+	if len(NewMapSet().SampleWithReplacement(3)) != 0 {
+		t.Error("sampling from an empty set should return an empty slice")
+	}
+}
+
+func Test_PopN(t *testing.T) {
+	for _, s := range []MapSet{makeSet([]int{1, 2, 3, 4, 5}), makeUnsafeSet([]int{1, 2, 3, 4, 5})} {
+		popped := s.PopN(2)
+		if len(popped) != 2 || s.RetElementCount() != 3 {
+			t.Fatalf("%T: expected 2 popped and 3 remaining, got %v and %d", s, popped, s.RetElementCount())
+		}
+		for _, elem := range popped {
+			if s.Contains(elem) {
+				t.Errorf("%T: popped element %v is still in the set", s, elem)
+			}
+		}
+
+		if len(s.PopN(10)) != 3 || s.RetElementCount() != 0 {
+			t.Errorf("%T: PopN larger than the set should drain it", s)
+		}
+	}
+}
+
+func Test_Shuffled(t *testing.T) {
+	for _, s := range []MapSet{makeSet([]int{1, 2, 3, 4, 5}), makeUnsafeSet([]int{1, 2, 3, 4, 5})} {
+		shuffled := s.Shuffled()
+		if len(shuffled) != 5 || !NewThreadUnsafeSetFromSlice(shuffled).Equal(s) {
+			t.Errorf("%T: Shuffled should return every element exactly once, got %v", s, shuffled)
+		}
+	}
+
+	a, b := makeSet([]int{1, 2, 3, 4, 5}), makeUnsafeSet([]int{1, 2, 3, 4, 5})
+	SetRandSeed(a, 11)
+	SetRandSeed(b, 11)
+	first, second := a.Shuffled(), b.Shuffled()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Shuffled should be reproducible with the same seed: %v != %v", first, second)
+		}
+	}
+}
+
+func Test_HashElemConsistentWithEqual(t *testing.T) {
+	a := 1
+	p := &a
+	before := hashElem(p)
+	*p = 2
+	if hashElem(p) != before {
+		t.Error("a pointer should hash by address, not by the value it points to")
+	}
+
+	zero := 0.0
+	if hashElem(zero) != hashElem(-zero) {
+		t.Error("+0.0 and -0.0 are == and should hash the same")
+	}
+	type pair struct {
+		X interface{}
+		P *int
+	}
+	if hashElem(pair{zero, p}) != hashElem(pair{-zero, p}) {
+		t.Error("equal structs should hash the same")
+	}
+}
+
+func Test_PopStructElements(t *testing.T) {
+	type job struct {
+		ID   int
+		Name string
+	}
+	for _, s := range []MapSet{NewMapSet(), NewThreadUnsafeSet()} {
+		const n = 20000
+		for i := 0; i < n; i++ {
+			s.Add(job{i, "job"})
+		}
+
+		seen := NewThreadUnsafeSet()
+		for s.RetElementCount() > 0 {
+			if s.RandomReturn() == nil {
+				t.Fatalf("%T: RandomReturn returned nil on a non-empty set", s)
+			}
+			seen.Add(s.Pop())
+		}
+		if seen.RetElementCount() != n {
+			t.Errorf("%T: expected %d distinct popped elements, got %d", s, n, seen.RetElementCount())
+		}
+	}
+}
+
+func Test_SetRandSource(t *testing.T) {
+	others := allSetKinds(1, 2, 3, 4, 5, 6, 7, 8)
+	for i, s := range allSetKinds(1, 2, 3, 4, 5, 6, 7, 8) {
+		if !SetRandSeed(s, 5) || !SetRandSeed(others[i], 5) {
+			t.Fatalf("%T: should accept a random source", s)
+		}
+		// Clear保留集合的随机数源,另一个集合的随机操作不影响它
+		s.Clear()
+		s.AddAll(1, 2, 3, 4, 5, 6, 7, 8)
+		NewMapSet(1, 2, 3).Shuffled()
+		first, second := s.Shuffled(), others[i].Shuffled()
+		for j := range first {
+			if first[j] != second[j] {
+				t.Fatalf("%T: Shuffled should be reproducible with the same seed: %v != %v", s, first, second)
+			}
+		}
+
+		if !SetRandSource(s, nil) || s.RandomReturn() == nil {
+			t.Errorf("%T: a nil source should restore the default", s)
+		}
+	}
+
+	if SetRandSeed(ReadOnly(NewMapSet()), 1) || SetRandSeed((*threadSafeSet)(nil), 1) || SetRandSource(nil, rand.NewPCG(1, 1)) {
+		t.Error("sets without random operations and nil sets should not accept a source")
+	}
+}
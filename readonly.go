@@ -0,0 +1,77 @@
+package mapSet
+
+import "iter"
+
+// readOnlySet只转发ReadOnlySet中的方法。被包装的MapSet保存在未导出的字段中,
+// 调用者无法通过类型断言取回它,也就无法修改集合
+type readOnlySet struct {
+	s MapSet
+}
+
+// ReadOnly返回s的只读视图。视图不复制元素,s之后的修改对视图立即可见,nil视为空集
+func ReadOnly(s MapSet) ReadOnlySet {
+	if isNilSet(s) {
+		s = NewThreadUnsafeSet()
+	}
+	return readOnlySet{s: s}
+}
+
+func (r readOnlySet) RetElementCount() int {
+	return r.s.RetElementCount()
+}
+
+func (r readOnlySet) Contains(i ...interface{}) bool {
+	return r.s.Contains(i...)
+}
+
+func (r readOnlySet) ContainsAny(items ...interface{}) bool {
+	return r.s.ContainsAny(items...)
+}
+
+func (r readOnlySet) ToSlice() []interface{} {
+	return r.s.ToSlice()
+}
+
+func (r readOnlySet) Freeze() FrozenSet {
+	return r.s.Freeze()
+}
+
+func (r readOnlySet) Equal(other ReadOnlySet) bool {
+	return r.s.Equal(other)
+}
+
+func (r readOnlySet) Each(cb func(interface{}) bool) {
+	r.s.Each(cb)
+}
+
+func (r readOnlySet) String(sep string) string {
+	return r.s.String(sep)
+}
+
+func (r readOnlySet) IsSubset(other ReadOnlySet) bool {
+	return r.s.IsSubset(other)
+}
+
+func (r readOnlySet) IsProperSubset(other ReadOnlySet) bool {
+	return r.s.IsProperSubset(other)
+}
+
+func (r readOnlySet) IsSuperset(other ReadOnlySet) bool {
+	return r.s.IsSuperset(other)
+}
+
+func (r readOnlySet) IsProperSuperset(other ReadOnlySet) bool {
+	return r.s.IsProperSuperset(other)
+}
+
+func (r readOnlySet) IsDisjoint(other ReadOnlySet) bool {
+	return r.s.IsDisjoint(other)
+}
+
+func (r readOnlySet) Iterator() *Iterator {
+	return r.s.Iterator()
+}
+
+func (r readOnlySet) All() iter.Seq[interface{}] {
+	return r.s.All()
+}
@@ -0,0 +1,70 @@
+package mapSet
+
+import "testing"
+
+func Test_ReadOnly(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3) {
+		r := ReadOnly(s)
+		if r.RetElementCount() != 3 || !r.Contains(1, 2, 3) || !r.Equal(s) {
+			t.Errorf("%T: unexpected read-only view %v", s, r.ToSlice())
+		}
+
+		s.Add(4)
+		if r.RetElementCount() != 4 || !r.Contains(4) {
+			t.Errorf("%T: the read-only view should see later changes", s)
+		}
+
+		if _, ok := r.(MapSet); ok {
+			t.Errorf("%T: the read-only view should not be a MapSet", s)
+		}
+		if _, ok := r.(*threadSafeSet); ok {
+			t.Errorf("%T: the read-only view should not expose the wrapped set", s)
+		}
+	}
+
+	if ReadOnly(nil).RetElementCount() != 0 {
+		t.Error("a nil set should give an empty read-only view")
+	}
+}
+
+func Test_ReadOnlySetImplementations(t *testing.T) {
+	views := []ReadOnlySet{
+		NewMapSet(1, 2),
+		ReadOnly(NewMapSet(1, 2)),
+		NewPersistentSet(1, 2),
+	}
+	for _, v := range views {
+		if !v.Equal(NewMapSet(2, 1)) || !v.IsProperSubset(NewMapSet(1, 2, 3)) {
+			t.Errorf("%T: unexpected result for %v", v, v.ToSlice())
+		}
+		if v.Freeze() != NewFrozenSet(1, 2) {
+			t.Errorf("%T: unexpected frozen set", v)
+		}
+	}
+}
+
+func Test_ReadOnlyAsArgument(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3) {
+		view := ReadOnly(makeUnsafeSet([]int{2, 3, 4}))
+		if !s.Equal(ReadOnly(s)) || !ReadOnly(s).Equal(ReadOnly(NewMapSet(3, 2, 1))) {
+			t.Errorf("%T: a set should equal read-only views of equal sets", s)
+		}
+		if !s.IsSuperset(NewPersistentSet(1, 2)) || !s.IsProperSubset(ReadOnly(NewMapSet(1, 2, 3, 4))) {
+			t.Errorf("%T: unexpected subset relation with a read-only argument", s)
+		}
+		if s.Union(view).RetElementCount() != 4 || s.Intersect(view).RetElementCount() != 2 {
+			t.Errorf("%T: unexpected set algebra with a read-only argument", s)
+		}
+		if s.Difference(view).RetElementCount() != 1 || s.SymmetricDifference(view).RetElementCount() != 2 {
+			t.Errorf("%T: unexpected set algebra with a read-only argument", s)
+		}
+
+		// 传入自身的只读视图与传入自身相同
+		if s.UnionWith(ReadOnly(s)) != 0 || s.IntersectWith(ReadOnly(s)) != 0 || s.RetElementCount() != 3 {
+			t.Errorf("%T: combining a set with its own view should not change it", s)
+		}
+		if s.UnionWith(view) != 1 || s.DifferenceWith(ReadOnly(s)) != 4 || s.RetElementCount() != 0 {
+			t.Errorf("%T: unexpected in-place operation with a read-only argument, got %v", s, s.ToSlice())
+		}
+	}
+}
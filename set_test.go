@@ -1,6 +1,10 @@
 package mapSet
 
-import "testing"
+import (
+	"fmt"
+	"math"
+	"testing"
+)
 
 func makeSet(ints []int) MapSet {
 	set := NewMapSet()
@@ -512,4 +516,674 @@ func Test_ToSliceUnthreadsafe(t *testing.T) {
 			t.Errorf("Set is missing element: %v", i)
 		}
 	}
-}
\ No newline at end of file
+}
+func Test_SetUnion(t *testing.T) {
+	a := NewMapSet()
+
+	b := NewMapSet()
+	b.Add(1)
+	b.Add(2)
+	b.Add(3)
+	b.Add(4)
+	b.Add(5)
+
+	c := a.Union(b)
+
+	if c.RetElementCount() != 5 {
+		t.Error("set c is unioned with an empty set and therefore should have 5 elements in it")
+	}
+
+	d := NewMapSet()
+	d.Add(10)
+	d.Add(14)
+	d.Add(0)
+
+	e := c.Union(d)
+	if e.RetElementCount() != 8 {
+		t.Error("set e should should have 8 elements in it after being unioned with set c to d")
+	}
+
+	f := NewMapSet()
+	f.Add(14)
+	f.Add(3)
+
+	g := f.Union(e)
+	if g.RetElementCount() != 8 {
+		t.Error("set g should still have 8 elements in it after being unioned with set f that has duplicates")
+	}
+}
+
+func Test_UnsafeSetUnion(t *testing.T) {
+	a := NewThreadUnsafeSet()
+
+	b := NewThreadUnsafeSet()
+	b.Add(1)
+	b.Add(2)
+	b.Add(3)
+	b.Add(4)
+	b.Add(5)
+
+	c := a.Union(b)
+
+	if c.RetElementCount() != 5 {
+		t.Error("set c is unioned with an empty set and therefore should have 5 elements in it")
+	}
+
+	d := NewThreadUnsafeSet()
+	d.Add(10)
+	d.Add(14)
+	d.Add(0)
+
+	e := c.Union(d)
+	if e.RetElementCount() != 8 {
+		t.Error("set e should should have 8 elements in it after being unioned with set c to d")
+	}
+
+	f := NewThreadUnsafeSet()
+	f.Add(14)
+	f.Add(3)
+
+	g := f.Union(e)
+	if g.RetElementCount() != 8 {
+		t.Error("set g should still have 8 elements in it after being unioned with set f that has duplicates")
+	}
+}
+
+func Test_SetIntersect(t *testing.T) {
+	a := makeSet([]int{1, 3, 5})
+	b := makeSet([]int{2, 4, 6})
+
+	c := a.Intersect(b)
+	if c.RetElementCount() != 0 {
+		t.Error("set c should be the empty set because there is no common items to intersect")
+	}
+
+	a.Add(10)
+	b.Add(10)
+
+	d := a.Intersect(b)
+	if !(d.RetElementCount() == 1 && d.Contains(10)) {
+		t.Error("set d should have a size of 1 and contain the item 10")
+	}
+}
+
+func Test_UnsafeSetIntersect(t *testing.T) {
+	a := makeUnsafeSet([]int{1, 3, 5})
+	b := makeUnsafeSet([]int{2, 4, 6})
+
+	c := a.Intersect(b)
+	if c.RetElementCount() != 0 {
+		t.Error("set c should be the empty set because there is no common items to intersect")
+	}
+
+	a.Add(10)
+	b.Add(10)
+
+	d := a.Intersect(b)
+	if !(d.RetElementCount() == 1 && d.Contains(10)) {
+		t.Error("set d should have a size of 1 and contain the item 10")
+	}
+}
+
+func Test_SetDifference(t *testing.T) {
+	a := makeSet([]int{1, 2, 3})
+	b := makeSet([]int{1, 3, 4, 5, 6, 99})
+
+	c := a.Difference(b)
+	if !(c.RetElementCount() == 1 && c.Contains(2)) {
+		t.Error("the difference of set a to b is the set of 1 item: 2")
+	}
+}
+
+func Test_UnsafeSetDifference(t *testing.T) {
+	a := makeUnsafeSet([]int{1, 2, 3})
+	b := makeUnsafeSet([]int{1, 3, 4, 5, 6, 99})
+
+	c := a.Difference(b)
+	if !(c.RetElementCount() == 1 && c.Contains(2)) {
+		t.Error("the difference of set a to b is the set of 1 item: 2")
+	}
+}
+
+func Test_SetSymmetricDifference(t *testing.T) {
+	a := makeSet([]int{1, 2, 3, 45})
+	b := makeSet([]int{1, 3, 4, 5, 6, 99})
+
+	c := a.SymmetricDifference(b)
+	if !(c.RetElementCount() == 6 && c.Contains(2, 45, 4, 5, 6, 99)) {
+		t.Error("the symmetric difference of set a to b is the set of 6 items: 2, 45, 4, 5, 6, 99")
+	}
+}
+
+func Test_UnsafeSetSymmetricDifference(t *testing.T) {
+	a := makeUnsafeSet([]int{1, 2, 3, 45})
+	b := makeUnsafeSet([]int{1, 3, 4, 5, 6, 99})
+
+	c := a.SymmetricDifference(b)
+	if !(c.RetElementCount() == 6 && c.Contains(2, 45, 4, 5, 6, 99)) {
+		t.Error("the symmetric difference of set a to b is the set of 6 items: 2, 45, 4, 5, 6, 99")
+	}
+}
+
+func Test_SetAlgebraKeepsKind(t *testing.T) {
+	a := makeSet([]int{1, 2})
+	b := makeSet([]int{2, 3})
+	for _, s := range []MapSet{a.Union(b), a.Intersect(b), a.Difference(b), a.SymmetricDifference(b)} {
+		if _, ok := s.(*threadSafeSet); !ok {
+			t.Errorf("expected *threadSafeSet, got %T", s)
+		}
+	}
+
+	c := makeUnsafeSet([]int{1, 2})
+	d := makeUnsafeSet([]int{2, 3})
+	for _, s := range []MapSet{c.Union(d), c.Intersect(d), c.Difference(d), c.SymmetricDifference(d)} {
+		if _, ok := s.(*threadUnsafeSet); !ok {
+			t.Errorf("expected *threadUnsafeSet, got %T", s)
+		}
+	}
+}
+
+func Test_SetIsSubset(t *testing.T) {
+	a := makeSet([]int{1, 2, 3, 5, 7})
+	b := makeSet([]int{3, 5, 7})
+
+	if !b.IsSubset(a) {
+		t.Error("set b should be a subset of set a")
+	}
+	if a.IsSubset(b) {
+		t.Error("set a should not be a subset of set b")
+	}
+
+	b.Add(72)
+
+	if b.IsSubset(a) {
+		t.Error("set b should not be a subset of set a because it contains 72 which is not in the set of a")
+	}
+	if !a.IsSubset(a) {
+		t.Error("set a should be a subset of itself")
+	}
+}
+
+func Test_UnsafeSetIsSubset(t *testing.T) {
+	a := makeUnsafeSet([]int{1, 2, 3, 5, 7})
+	b := makeUnsafeSet([]int{3, 5, 7})
+
+	if !b.IsSubset(a) {
+		t.Error("set b should be a subset of set a")
+	}
+	if a.IsSubset(b) {
+		t.Error("set a should not be a subset of set b")
+	}
+
+	b.Add(72)
+
+	if b.IsSubset(a) {
+		t.Error("set b should not be a subset of set a because it contains 72 which is not in the set of a")
+	}
+}
+
+func Test_SetIsProperSubset(t *testing.T) {
+	a := makeSet([]int{1, 2, 3, 5, 7})
+	b := makeSet([]int{7, 5, 3, 2, 1})
+
+	if !a.IsSubset(b) {
+		t.Error("set a should be a subset of set b")
+	}
+	if a.IsProperSubset(b) {
+		t.Error("set a should not be a proper subset of set b (they're equal)")
+	}
+
+	b.Add(72)
+
+	if !a.IsProperSubset(b) {
+		t.Error("set a should be a proper subset of set b")
+	}
+}
+
+func Test_UnsafeSetIsProperSubset(t *testing.T) {
+	a := makeUnsafeSet([]int{1, 2, 3, 5, 7})
+	b := makeUnsafeSet([]int{7, 5, 3, 2, 1})
+
+	if a.IsProperSubset(b) {
+		t.Error("set a should not be a proper subset of set b (they're equal)")
+	}
+
+	b.Add(72)
+
+	if !a.IsProperSubset(b) {
+		t.Error("set a should be a proper subset of set b")
+	}
+}
+
+func Test_SetIsSuperset(t *testing.T) {
+	a := makeSet([]int{9, 5, 2, 1, 11})
+	b := makeSet([]int{5, 2, 11})
+
+	if !a.IsSuperset(b) {
+		t.Error("set a should be a superset of set b")
+	}
+	if b.IsSuperset(a) {
+		t.Error("set b should not be a superset of set a")
+	}
+
+	b.Add(42)
+
+	if a.IsSuperset(b) {
+		t.Error("set a should not be a superset of set b because set b has a 42")
+	}
+}
+
+func Test_UnsafeSetIsSuperset(t *testing.T) {
+	a := makeUnsafeSet([]int{9, 5, 2, 1, 11})
+	b := makeUnsafeSet([]int{5, 2, 11})
+
+	if !a.IsSuperset(b) {
+		t.Error("set a should be a superset of set b")
+	}
+
+	b.Add(42)
+
+	if a.IsSuperset(b) {
+		t.Error("set a should not be a superset of set b because set b has a 42")
+	}
+}
+
+func Test_SetIsProperSuperset(t *testing.T) {
+	a := makeSet([]int{5, 2, 11})
+	b := makeSet([]int{2, 5, 11})
+
+	if !a.IsSuperset(b) {
+		t.Error("set a should be a superset of set b")
+	}
+	if a.IsProperSuperset(b) {
+		t.Error("set a should not be a proper superset of set b (they're equal)")
+	}
+
+	a.Add(9)
+
+	if !a.IsProperSuperset(b) {
+		t.Error("set a should be a proper superset of set b")
+	}
+	if b.IsProperSuperset(a) {
+		t.Error("set b should not be a proper superset of set a")
+	}
+}
+
+func Test_UnsafeSetIsProperSuperset(t *testing.T) {
+	a := makeUnsafeSet([]int{5, 2, 11})
+	b := makeUnsafeSet([]int{2, 5, 11})
+
+	if a.IsProperSuperset(b) {
+		t.Error("set a should not be a proper superset of set b (they're equal)")
+	}
+
+	a.Add(9)
+
+	if !a.IsProperSuperset(b) {
+		t.Error("set a should be a proper superset of set b")
+	}
+}
+
+func Test_SetIsDisjoint(t *testing.T) {
+	a := makeSet([]int{1, 3, 5})
+	b := makeSet([]int{2, 4, 6, 8})
+
+	if !a.IsDisjoint(b) || !b.IsDisjoint(a) {
+		t.Error("set a and set b should be disjoint")
+	}
+
+	b.Add(5)
+
+	if a.IsDisjoint(b) || b.IsDisjoint(a) {
+		t.Error("set a and set b share 5 and should not be disjoint")
+	}
+	if !a.IsDisjoint(NewMapSet()) {
+		t.Error("any set should be disjoint with the empty set")
+	}
+}
+
+func Test_UnsafeSetIsDisjoint(t *testing.T) {
+	a := makeUnsafeSet([]int{1, 3, 5})
+	b := makeUnsafeSet([]int{2, 4, 6, 8})
+
+	if !a.IsDisjoint(b) || !b.IsDisjoint(a) {
+		t.Error("set a and set b should be disjoint")
+	}
+
+	b.Add(5)
+
+	if a.IsDisjoint(b) || b.IsDisjoint(a) {
+		t.Error("set a and set b share 5 and should not be disjoint")
+	}
+}
+
+func Test_MixedSetPredicates(t *testing.T) {
+	a := makeSet([]int{1, 2, 3})
+	b := makeUnsafeSet([]int{1, 2})
+
+	if !b.IsProperSubset(a) || !a.IsProperSuperset(b) {
+		t.Error("unsafe set b should be a proper subset of safe set a")
+	}
+	if a.IsSubset(b) || b.IsSuperset(a) {
+		t.Error("safe set a should not be a subset of unsafe set b")
+	}
+	if a.IsDisjoint(b) || b.IsDisjoint(a) {
+		t.Error("set a and set b should not be disjoint")
+	}
+}
+
+func Test_MixedSetEqual(t *testing.T) {
+	a := makeSet([]int{1, 2, 3})
+	b := makeUnsafeSet([]int{3, 2, 1})
+
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Error("a safe set and an unsafe set with the same elements should be equal")
+	}
+
+	b.Add(4)
+
+	if a.Equal(b) || b.Equal(a) {
+		t.Error("b has one more element, they should not be equal")
+	}
+}
+
+func Test_NilSetEqual(t *testing.T) {
+	var nilSafe *threadSafeSet
+	var nilUnsafe *threadUnsafeSet
+
+	if !NewMapSet().Equal(nil) || !NewThreadUnsafeSet().Equal(nil) {
+		t.Error("an empty set should be equal to nil")
+	}
+	if makeSet([]int{1}).Equal(nil) || makeUnsafeSet([]int{1}).Equal(nilSafe) {
+		t.Error("a non-empty set should not be equal to nil")
+	}
+	if !nilSafe.Equal(nilUnsafe) || !nilUnsafe.Equal(nil) {
+		t.Error("nil sets should be equal to each other")
+	}
+	if nilSafe.Equal(makeSet([]int{1})) {
+		t.Error("a nil set should not be equal to a non-empty set")
+	}
+}
+
+func Test_MixedSetAlgebra(t *testing.T) {
+	a := makeSet([]int{1, 2, 3})
+	b := makeUnsafeSet([]int{2, 3, 4})
+
+	assertEqual(a.Union(b), makeSet([]int{1, 2, 3, 4}), t)
+	assertEqual(b.Union(a), makeSet([]int{1, 2, 3, 4}), t)
+	assertEqual(a.Intersect(b), makeSet([]int{2, 3}), t)
+	assertEqual(b.Intersect(a), makeSet([]int{2, 3}), t)
+	assertEqual(a.Difference(b), makeSet([]int{1}), t)
+	assertEqual(b.Difference(a), makeSet([]int{4}), t)
+	assertEqual(a.SymmetricDifference(b), makeSet([]int{1, 4}), t)
+	assertEqual(b.SymmetricDifference(a), makeSet([]int{1, 4}), t)
+
+	assertEqual(a.Union(nil), a, t)
+	assertEqual(b.Intersect(nil), NewMapSet(), t)
+}
+
+func Test_RemoveIf(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4, 5, 6) {
+		removed := s.RemoveIf(func(elem interface{}) bool {
+			return elem.(int)%2 == 0
+		})
+		if removed != 3 {
+			t.Errorf("%T: RemoveIf should report 3 removed elements, got %d", s, removed)
+		}
+		assertEqual(s, makeSet([]int{1, 3, 5}), t)
+	}
+}
+
+func Test_RetainIf(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4, 5, 6) {
+		removed := s.RetainIf(func(elem interface{}) bool {
+			return elem.(int) > 4
+		})
+		if removed != 4 {
+			t.Errorf("%T: RetainIf should report 4 removed elements, got %d", s, removed)
+		}
+		assertEqual(s, makeSet([]int{5, 6}), t)
+	}
+}
+
+func Test_EachMutable(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		visited := NewThreadUnsafeSet()
+		s.EachMutable(func(elem interface{}, set MapSet) bool {
+			visited.Add(elem)
+			// 删除配对的元素,被删除且尚未遍历到的元素不应再被遍历
+			if elem.(int)%2 == 1 {
+				set.Remove(elem.(int) + 1)
+			} else {
+				set.Remove(elem.(int) - 1)
+			}
+			set.Add(elem.(int) * 10)
+			return false
+		})
+
+		if visited.RetElementCount() != 2 {
+			t.Errorf("%T: EachMutable should skip removed elements, visited %v", s, visited.ToSlice())
+		}
+		if s.RetElementCount() != 4 || !s.Contains(visited.ToSlice()...) {
+			t.Errorf("%T: unexpected set after EachMutable: %v", s, s.ToSlice())
+		}
+	}
+}
+
+func Test_EachMutableStop(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		count := 0
+		s.EachMutable(func(elem interface{}, set MapSet) bool {
+			count++
+			return count == 2
+		})
+		if count != 2 {
+			t.Errorf("%T: EachMutable should stop when the callback returns true", s)
+		}
+	}
+}
+
+func Test_AddAll(t *testing.T) {
+	for _, s := range allSetKinds(1, 2) {
+		added := s.AddAll(2, 3, 4, 3)
+		if added != 2 {
+			t.Errorf("%T: AddAll should report 2 newly added elements, got %d", s, added)
+		}
+		assertEqual(s, makeSet([]int{1, 2, 3, 4}), t)
+
+		if s.AddAll() != 0 {
+			t.Errorf("%T: AddAll without items should add nothing", s)
+		}
+	}
+}
+
+func Test_RemoveAll(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		removed := s.RemoveAll(2, 4, 6, 4)
+		if removed != 2 {
+			t.Errorf("%T: RemoveAll should report 2 removed elements, got %d", s, removed)
+		}
+		assertEqual(s, makeSet([]int{1, 3}), t)
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	for _, s := range allSetKinds(1, 2) {
+		if !s.Delete(1) {
+			t.Errorf("%T: Delete should report that 1 was present", s)
+		}
+		if s.Delete(1) {
+			t.Errorf("%T: Delete should report that 1 is no longer present", s)
+		}
+		assertEqual(s, makeSet([]int{2}), t)
+	}
+}
+
+func Test_ContainsAny(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3) {
+		if !s.ContainsAny(7, 8, 3) {
+			t.Errorf("%T: ContainsAny should find 3", s)
+		}
+		if s.ContainsAny(7, 8, 9) {
+			t.Errorf("%T: ContainsAny should not find any of 7, 8, 9", s)
+		}
+		if s.ContainsAny() {
+			t.Errorf("%T: ContainsAny without items should be false", s)
+		}
+	}
+}
+
+func Test_UnionWith(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3) {
+		if changed := s.UnionWith(makeUnsafeSet([]int{3, 4, 5})); changed != 2 {
+			t.Errorf("%T: UnionWith should report 2 added elements, got %d", s, changed)
+		}
+		assertEqual(s, makeSet([]int{1, 2, 3, 4, 5}), t)
+
+		if s.UnionWith(s) != 0 || s.UnionWith(nil) != 0 {
+			t.Errorf("%T: UnionWith itself or nil should not change the set", s)
+		}
+	}
+}
+
+func Test_IntersectWith(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		if changed := s.IntersectWith(makeSet([]int{2, 4, 6})); changed != 2 {
+			t.Errorf("%T: IntersectWith should report 2 removed elements, got %d", s, changed)
+		}
+		assertEqual(s, makeSet([]int{2, 4}), t)
+
+		if s.IntersectWith(s) != 0 {
+			t.Errorf("%T: IntersectWith itself should not change the set", s)
+		}
+		if s.IntersectWith(nil) != 2 || s.RetElementCount() != 0 {
+			t.Errorf("%T: IntersectWith nil should empty the set", s)
+		}
+	}
+}
+
+func Test_DifferenceWith(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3, 4) {
+		if changed := s.DifferenceWith(makeSet([]int{2, 4, 6})); changed != 2 {
+			t.Errorf("%T: DifferenceWith should report 2 removed elements, got %d", s, changed)
+		}
+		assertEqual(s, makeSet([]int{1, 3}), t)
+
+		if s.DifferenceWith(nil) != 0 {
+			t.Errorf("%T: DifferenceWith nil should not change the set", s)
+		}
+		if s.DifferenceWith(s) != 2 || s.RetElementCount() != 0 {
+			t.Errorf("%T: DifferenceWith itself should empty the set", s)
+		}
+	}
+}
+
+func Test_SymmetricDifferenceWith(t *testing.T) {
+	for _, s := range allSetKinds(1, 2, 3) {
+		if changed := s.SymmetricDifferenceWith(makeUnsafeSet([]int{3, 4})); changed != 2 {
+			t.Errorf("%T: SymmetricDifferenceWith should report 2 changes, got %d", s, changed)
+		}
+		assertEqual(s, makeSet([]int{1, 2, 4}), t)
+
+		if s.SymmetricDifferenceWith(s) != 3 || s.RetElementCount() != 0 {
+			t.Errorf("%T: SymmetricDifferenceWith itself should empty the set", s)
+		}
+	}
+}
+
+func Test_UnionAll(t *testing.T) {
+	u := UnionAll(makeSet([]int{1, 2}), makeUnsafeSet([]int{2, 3}), nil, NewOrderedSet(FIFO, 4))
+	assertEqual(u, makeSet([]int{1, 2, 3, 4}), t)
+	if _, ok := u.(*threadSafeSet); !ok {
+		t.Errorf("UnionAll should return the kind of the first set, got %T", u)
+	}
+
+	u = UnionAll(makeUnsafeSet([]int{1}), makeSet([]int{2}))
+	if _, ok := u.(*threadUnsafeSet); !ok {
+		t.Errorf("UnionAll should return the kind of the first set, got %T", u)
+	}
+
+	if UnionAll().RetElementCount() != 0 {
+		t.Error("UnionAll without sets should be empty")
+	}
+}
+
+func Test_NaryTypedNilFirst(t *testing.T) {
+	typedNils := []MapSet{
+		(*threadUnsafeSet)(nil),
+		(*threadSafeSet)(nil),
+		(*copyOnWriteSet)(nil),
+		(*shardedSet)(nil),
+		(*threadUnsafeOrderedSet)(nil),
+		(*threadSafeOrderedSet)(nil),
+		(*threadUnsafeSortedSet)(nil),
+		(*threadSafeSortedSet)(nil),
+	}
+	for _, first := range typedNils {
+		assertEqual(UnionAll(first, makeSet([]int{1, 2})), makeSet([]int{1, 2}), t)
+		if IntersectAll(first, makeSet([]int{1, 2})).RetElementCount() != 0 {
+			t.Errorf("IntersectAll with a nil %T should be empty", first)
+		}
+	}
+}
+
+func Test_IntersectAll(t *testing.T) {
+	i := IntersectAll(
+		makeSet([]int{1, 2, 3, 4, 5}),
+		makeUnsafeSet([]int{2, 3, 4}),
+		NewSortedSet(OrderedComparator[int](), 3, 4, 5, 6),
+	)
+	assertEqual(i, makeSet([]int{3, 4}), t)
+	if _, ok := i.(*threadSafeSet); !ok {
+		t.Errorf("IntersectAll should return the kind of the first set, got %T", i)
+	}
+
+	// 较小的集合先参与计算,交集为空后不再访问其余集合
+	tiny := makeUnsafeSet([]int{100})
+	if IntersectAll(makeSet([]int{1, 2, 3}), tiny, makeSet([]int{1})).RetElementCount() != 0 {
+		t.Error("the intersection should be empty")
+	}
+
+	if IntersectAll(makeSet([]int{1}), nil).RetElementCount() != 0 {
+		t.Error("the intersection with nil should be empty")
+	}
+	assertEqual(IntersectAll(makeSet([]int{1, 2})), makeSet([]int{1, 2}), t)
+	if IntersectAll().RetElementCount() != 0 {
+		t.Error("IntersectAll without sets should be empty")
+	}
+}
+
+// 所有实现都按==判断元素是否相同:指针按地址比较,修改指向的值不会产生重复元素,0.0与-0.0是同一个元素
+func Test_ElementsFollowEquality(t *testing.T) {
+	type adder struct {
+		name string
+		add  func(v interface{}) ReadOnlySet
+	}
+	var adders []adder
+	for _, s := range allSetKinds() {
+		adders = append(adders, adder{fmt.Sprintf("%T", s), func(v interface{}) ReadOnlySet {
+			s.Add(v)
+			return s
+		}})
+	}
+	var p PersistentSet
+	adders = append(adders, adder{"PersistentSet", func(v interface{}) ReadOnlySet {
+		p = p.With(v)
+		return p
+	}})
+
+	for _, a := range adders {
+		x := 1
+		a.add(&x)
+		x = 2
+		if s := a.add(&x); s.RetElementCount() != 1 || !s.Contains(&x) {
+			t.Errorf("%s: changing a pointee should not duplicate the pointer, got %v", a.name, s.ToSlice())
+		}
+
+		negZero := math.Copysign(0, -1)
+		a.add(0.0)
+		if s := a.add(negZero); s.RetElementCount() != 2 || !s.Contains(negZero, 0.0) {
+			t.Errorf("%s: 0.0 and -0.0 should be one element, got %v", a.name, s.ToSlice())
+		}
+	}
+}
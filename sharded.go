@@ -0,0 +1,494 @@
+package mapSet
+
+import (
+	"iter"
+	"sync"
+)
+
+// defaultShardCount是NewShardedSet未指定分片数时使用的分片数
+const defaultShardCount = 32
+
+// setShard是shardedSet的一个分片,末尾的填充使相邻分片的锁不在同一缓存行
+type setShard struct {
+	s threadUnsafeSet
+	sync.RWMutex
+	_ [64]byte
+}
+
+// shardedSet把元素按哈希值分散到多个各自加锁的分片中,访问不同分片的写操作可以并行执行。
+// 只涉及一个元素的操作只锁对应的分片;涉及整个集合的操作按分片顺序锁住所有分片,结果是一致的
+type shardedSet struct {
+	shards []setShard
+	// rnd是SetRandSource设置的随机数源,在持有所有分片的锁时访问
+	rnd *randSource
+}
+
+// NewShardedSet创建分片的线程安全MapSet,shards为分片数,不大于0时使用默认值32。
+// 多个goroutine频繁增删不同元素时比NewMapSet的争用少,
+// 但RetElementCount,ToSlice等涉及整个集合的操作需要锁住所有分片,代价更高
+func NewShardedSet(shards int, s ...interface{}) MapSet {
+	set := newShardedSet(shards)
+	for _, value := range s {
+		set.Add(value)
+	}
+	return set
+}
+
+func newShardedSet(shards int) *shardedSet {
+	if shards <= 0 {
+		shards = defaultShardCount
+	}
+	set := &shardedSet{shards: make([]setShard, shards)}
+	for i := range set.shards {
+		set.shards[i].s = newThreadUnsafeSet()
+	}
+	return set
+}
+
+func (set *shardedSet) shardOf(i interface{}) *setShard {
+	return &set.shards[hashElem(i)%uint64(len(set.shards))]
+}
+
+// 按下标顺序给所有分片加锁,所有涉及多个分片的操作都使用这一顺序,避免死锁
+
+func (set *shardedSet) lockAll() {
+	for i := range set.shards {
+		set.shards[i].Lock()
+	}
+}
+
+func (set *shardedSet) unlockAll() {
+	for i := range set.shards {
+		set.shards[i].Unlock()
+	}
+}
+
+func (set *shardedSet) rlockAll() {
+	for i := range set.shards {
+		set.shards[i].RLock()
+	}
+}
+
+func (set *shardedSet) runlockAll() {
+	for i := range set.shards {
+		set.shards[i].RUnlock()
+	}
+}
+
+// merged把所有分片合并为一个非线程安全的集合,调用者需要持有所有分片的锁
+func (set *shardedSet) merged() *threadUnsafeSet {
+	ret := newThreadUnsafeSet()
+	for i := range set.shards {
+		// 按items的顺序合并,使相同操作序列得到的快照顺序相同,固定种子时Sample和Shuffled可以复现
+		for _, elem := range set.shards[i].s.items {
+			ret.Add(elem)
+		}
+	}
+	return &ret
+}
+
+// randomLocked按分片的元素个数加权选出一个分片,再在分片中等概率地选出一个元素,
+// 因此每个元素被选中的概率相同。不合并分片,时间为O(分片数)。调用者需要持有所有分片的锁,集合为空时返回nil
+func (set *shardedSet) randomLocked() (*setShard, interface{}) {
+	total := 0
+	for i := range set.shards {
+		total += set.shards[i].s.RetElementCount()
+	}
+	if total == 0 {
+		return nil, nil
+	}
+	r := set.rnd.intn(total)
+	for i := range set.shards {
+		shard := &set.shards[i]
+		if n := shard.s.RetElementCount(); r >= n {
+			r -= n
+			continue
+		}
+		return shard, shard.s.items[r]
+	}
+	return nil, nil
+}
+
+// snapshot返回集合当前内容的一致快照,快照沿用集合的随机数源,用于Sample和Shuffled
+func (set *shardedSet) snapshot() *threadUnsafeSet {
+	set.rlockAll()
+	defer set.runlockAll()
+	ret := set.merged()
+	ret.rnd = set.rnd
+	return ret
+}
+
+// emptyLike返回分片数相同的空集合
+func (set *shardedSet) emptyLike() *shardedSet {
+	return newShardedSet(len(set.shards))
+}
+
+// fromUnsafe返回与调用者分片数相同,元素为s的新集合
+func (set *shardedSet) fromUnsafe(s MapSet) MapSet {
+	ret := set.emptyLike()
+	s.Each(func(elem interface{}) bool {
+		ret.shardOf(elem).s.Add(elem)
+		return false
+	})
+	return ret
+}
+
+// snapshotOther返回other的一致快照,用于与other的运算。
+// 不带锁的实现直接使用,其他实现先复制元素,因此不会同时持有两个集合的锁
+func snapshotOther(other ReadOnlySet) ReadOnlySet {
+	other = orEmpty(other)
+	if isThreadUnsafe(other) {
+		return other
+	}
+	if o, ok := other.(*copyOnWriteSet); ok {
+		return o.load()
+	}
+	return NewThreadUnsafeSetFromSlice(other.ToSlice())
+}
+
+func (set *shardedSet) Add(i interface{}) bool {
+	shard := set.shardOf(i)
+	shard.Lock()
+	ret := shard.s.Add(i)
+	shard.Unlock()
+	return ret
+}
+
+func (set *shardedSet) Contains(i ...interface{}) bool {
+	if len(i) == 1 {
+		shard := set.shardOf(i[0])
+		shard.RLock()
+		ret := shard.s.Contains(i[0])
+		shard.RUnlock()
+		return ret
+	}
+
+	set.rlockAll()
+	defer set.runlockAll()
+	for _, val := range i {
+		if !set.shardOf(val).s.Contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *shardedSet) Clear() {
+	set.lockAll()
+	for i := range set.shards {
+		set.shards[i].s = newThreadUnsafeSet()
+	}
+	set.unlockAll()
+}
+
+func (set *shardedSet) setRandSource(r *randSource) {
+	set.lockAll()
+	set.rnd = r
+	set.unlockAll()
+}
+
+func (set *shardedSet) Remove(i interface{}) {
+	set.Delete(i)
+}
+
+func (set *shardedSet) Delete(i interface{}) bool {
+	shard := set.shardOf(i)
+	shard.Lock()
+	ret := shard.s.Delete(i)
+	shard.Unlock()
+	return ret
+}
+
+func (set *shardedSet) AddAll(items ...interface{}) int {
+	set.lockAll()
+	defer set.unlockAll()
+	n := 0
+	for _, item := range items {
+		if set.shardOf(item).s.Add(item) {
+			n++
+		}
+	}
+	return n
+}
+
+func (set *shardedSet) RemoveAll(items ...interface{}) int {
+	set.lockAll()
+	defer set.unlockAll()
+	n := 0
+	for _, item := range items {
+		if set.shardOf(item).s.Delete(item) {
+			n++
+		}
+	}
+	return n
+}
+
+func (set *shardedSet) ContainsAny(items ...interface{}) bool {
+	set.rlockAll()
+	defer set.runlockAll()
+	for _, item := range items {
+		if set.shardOf(item).s.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *shardedSet) RetElementCount() int {
+	set.rlockAll()
+	defer set.runlockAll()
+	n := 0
+	for i := range set.shards {
+		n += set.shards[i].s.RetElementCount()
+	}
+	return n
+}
+
+func (set *shardedSet) Each(cb func(interface{}) bool) {
+	set.rlockAll()
+	defer set.runlockAll()
+	for i := range set.shards {
+		stop := false
+		set.shards[i].s.Each(func(elem interface{}) bool {
+			stop = cb(elem)
+			return stop
+		})
+		if stop {
+			return
+		}
+	}
+}
+
+func (set *shardedSet) RemoveIf(pred func(interface{}) bool) int {
+	set.lockAll()
+	defer set.unlockAll()
+	n := 0
+	for i := range set.shards {
+		n += set.shards[i].s.RemoveIf(pred)
+	}
+	return n
+}
+
+func (set *shardedSet) RetainIf(pred func(interface{}) bool) int {
+	set.lockAll()
+	defer set.unlockAll()
+	n := 0
+	for i := range set.shards {
+		n += set.shards[i].s.RetainIf(pred)
+	}
+	return n
+}
+
+// EachMutable在锁住所有分片时对合并后的集合调用回调,结束后把结果重新分配到各分片
+func (set *shardedSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	set.lockAll()
+	defer set.unlockAll()
+	merged := set.merged()
+	merged.EachMutable(cb)
+	for i := range set.shards {
+		set.shards[i].s = newThreadUnsafeSet()
+	}
+	merged.Each(func(elem interface{}) bool {
+		set.shardOf(elem).s.Add(elem)
+		return false
+	})
+}
+
+func (set *shardedSet) Pop() interface{} {
+	set.lockAll()
+	defer set.unlockAll()
+	shard, item := set.randomLocked()
+	if shard != nil {
+		shard.s.Remove(item)
+	}
+	return item
+}
+
+func (set *shardedSet) PopN(k int) []interface{} {
+	set.lockAll()
+	defer set.unlockAll()
+	items := make([]interface{}, 0)
+	for len(items) < k {
+		shard, item := set.randomLocked()
+		if shard == nil {
+			break
+		}
+		shard.s.Remove(item)
+		items = append(items, item)
+	}
+	return items
+}
+
+func (set *shardedSet) Equal(other ReadOnlySet) bool {
+	return set.snapshot().Equal(snapshotOther(other))
+}
+
+func (set *shardedSet) Union(other ReadOnlySet) MapSet {
+	return set.fromUnsafe(set.snapshot().Union(snapshotOther(other)))
+}
+
+func (set *shardedSet) Intersect(other ReadOnlySet) MapSet {
+	return set.fromUnsafe(set.snapshot().Intersect(snapshotOther(other)))
+}
+
+func (set *shardedSet) Difference(other ReadOnlySet) MapSet {
+	return set.fromUnsafe(set.snapshot().Difference(snapshotOther(other)))
+}
+
+func (set *shardedSet) SymmetricDifference(other ReadOnlySet) MapSet {
+	return set.fromUnsafe(set.snapshot().SymmetricDifference(snapshotOther(other)))
+}
+
+// 以下原地运算先取得other的快照再锁住所有分片,other是调用者自身时也不会死锁
+
+func (set *shardedSet) UnionWith(other ReadOnlySet) int {
+	o := snapshotOther(other)
+	set.lockAll()
+	defer set.unlockAll()
+	n := 0
+	o.Each(func(elem interface{}) bool {
+		if set.shardOf(elem).s.Add(elem) {
+			n++
+		}
+		return false
+	})
+	return n
+}
+
+func (set *shardedSet) IntersectWith(other ReadOnlySet) int {
+	o := snapshotOther(other)
+	set.lockAll()
+	defer set.unlockAll()
+	n := 0
+	for i := range set.shards {
+		n += set.shards[i].s.RetainIf(func(elem interface{}) bool {
+			return o.Contains(elem)
+		})
+	}
+	return n
+}
+
+func (set *shardedSet) DifferenceWith(other ReadOnlySet) int {
+	o := snapshotOther(other)
+	set.lockAll()
+	defer set.unlockAll()
+	n := 0
+	o.Each(func(elem interface{}) bool {
+		if set.shardOf(elem).s.Delete(elem) {
+			n++
+		}
+		return false
+	})
+	return n
+}
+
+func (set *shardedSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	o := snapshotOther(other)
+	set.lockAll()
+	defer set.unlockAll()
+	n := 0
+	o.Each(func(elem interface{}) bool {
+		shard := set.shardOf(elem)
+		if !shard.s.Delete(elem) {
+			shard.s.Add(elem)
+		}
+		n++
+		return false
+	})
+	return n
+}
+
+func (set *shardedSet) IsSubset(other ReadOnlySet) bool {
+	return set.snapshot().IsSubset(snapshotOther(other))
+}
+
+func (set *shardedSet) IsProperSubset(other ReadOnlySet) bool {
+	return set.snapshot().IsProperSubset(snapshotOther(other))
+}
+
+func (set *shardedSet) IsSuperset(other ReadOnlySet) bool {
+	return set.snapshot().IsSuperset(snapshotOther(other))
+}
+
+func (set *shardedSet) IsProperSuperset(other ReadOnlySet) bool {
+	return set.snapshot().IsProperSuperset(snapshotOther(other))
+}
+
+func (set *shardedSet) IsDisjoint(other ReadOnlySet) bool {
+	return set.snapshot().IsDisjoint(snapshotOther(other))
+}
+
+// Clone复制每个分片,分片数与原集合相同,元素所在的分片也不变
+func (set *shardedSet) Clone() MapSet {
+	set.rlockAll()
+	defer set.runlockAll()
+	ret := set.emptyLike()
+	ret.rnd = set.rnd
+	for i := range set.shards {
+		ret.shards[i].s = *set.shards[i].s.Clone().(*threadUnsafeSet)
+	}
+	return ret
+}
+
+func (set *shardedSet) String(sep string) string {
+	return set.snapshot().String(sep)
+}
+
+func (set *shardedSet) RandomReturn() interface{} {
+	set.rlockAll()
+	defer set.runlockAll()
+	_, item := set.randomLocked()
+	return item
+}
+
+func (set *shardedSet) Sample(k int) []interface{} {
+	return set.snapshot().Sample(k)
+}
+
+func (set *shardedSet) SampleWithReplacement(k int) []interface{} {
+	return set.snapshot().SampleWithReplacement(k)
+}
+
+func (set *shardedSet) Shuffled() []interface{} {
+	return set.snapshot().Shuffled()
+}
+
+func (set *shardedSet) ToSlice() []interface{} {
+	set.rlockAll()
+	defer set.runlockAll()
+	n := 0
+	for i := range set.shards {
+		n += set.shards[i].s.RetElementCount()
+	}
+	keys := make([]interface{}, 0, n)
+	for i := range set.shards {
+		keys = append(keys, set.shards[i].s.items...)
+	}
+	return keys
+}
+
+func (set *shardedSet) Freeze() FrozenSet {
+	return newFrozenSet(set.ToSlice())
+}
+
+func (set *shardedSet) Iterator() *Iterator {
+	return newIterator(set.ToSlice())
+}
+
+func (set *shardedSet) All() iter.Seq[interface{}] {
+	return snapshotSeq(set.ToSlice)
+}
+
+func (set *shardedSet) MarshalJSON() ([]byte, error) {
+	return marshalJSONElems(set.ToSlice())
+}
+
+// UnmarshalJSON先在锁外解码JSON数组,再把元素加入集合
+func (set *shardedSet) UnmarshalJSON(b []byte) error {
+	decoded := newThreadUnsafeSet()
+	if err := decoded.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	set.AddAll(decoded.ToSlice()...)
+	return nil
+}
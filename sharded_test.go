@@ -0,0 +1,121 @@
+package mapSet
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func Test_ShardedSetAcrossShards(t *testing.T) {
+	for _, shards := range []int{0, 1, 3, 16} {
+		s := NewShardedSet(shards)
+		for i := 0; i < 100; i++ {
+			s.Add(i)
+		}
+		expected := NewThreadUnsafeSet()
+		for i := 0; i < 100; i++ {
+			expected.Add(i)
+		}
+
+		if s.RetElementCount() != 100 || len(s.ToSlice()) != 100 {
+			t.Errorf("%d shards: expected 100 elements, got %d", shards, s.RetElementCount())
+		}
+		if !s.Equal(expected) || !expected.Equal(s) || !s.Equal(s.Clone()) {
+			t.Errorf("%d shards: set should equal a plain set with the same elements", shards)
+		}
+		if !s.Equal(NewShardedSet(shards+1, expected.ToSlice()...)) {
+			t.Errorf("%d shards: sets with different shard counts should be equal", shards)
+		}
+
+		c := s.Clone()
+		c.Remove(1)
+		if !s.Contains(1) || c.RetElementCount() != 99 {
+			t.Errorf("%d shards: modifying the clone should not affect the set", shards)
+		}
+	}
+}
+
+func Test_ShardedSetSelfOperations(t *testing.T) {
+	s := NewShardedSet(4, 1, 2, 3)
+	if s.UnionWith(s) != 0 || s.IntersectWith(s) != 0 || !s.IsSubset(s) {
+		t.Error("operations with itself should not deadlock or change the set")
+	}
+	if s.DifferenceWith(s) != 3 || s.RetElementCount() != 0 {
+		t.Errorf("expected an empty set, got %v", s.ToSlice())
+	}
+}
+
+func Test_ShardedSetPop(t *testing.T) {
+	s := NewShardedSet(8)
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+	if !s.Contains(s.RandomReturn()) || s.RetElementCount() != 100 {
+		t.Error("RandomReturn should return an element without removing it")
+	}
+
+	seen := NewThreadUnsafeSet()
+	for _, item := range s.PopN(40) {
+		seen.Add(item)
+	}
+	for item := s.Pop(); item != nil; item = s.Pop() {
+		if !seen.Add(item) {
+			t.Fatalf("%v was popped twice", item)
+		}
+	}
+	if seen.RetElementCount() != 100 || s.RetElementCount() != 0 {
+		t.Errorf("expected all 100 elements to be popped, got %d", seen.RetElementCount())
+	}
+	if s.Pop() != nil || s.RandomReturn() != nil || len(s.PopN(3)) != 0 {
+		t.Error("an empty set should have nothing to pop")
+	}
+}
+
+func Test_ShardedSetConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewShardedSet(8)
+	ints := rand.Perm(N)
+
+	var wg sync.WaitGroup
+	wg.Add(len(ints))
+	for i := 0; i < len(ints); i++ {
+		go func(i int) {
+			s.Add(i)
+			s.RetElementCount()
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	if s.RetElementCount() != N {
+		t.Errorf("expected %d elements, got %d", N, s.RetElementCount())
+	}
+	for _, i := range ints {
+		if !s.Contains(i) {
+			t.Errorf("Set is missing element: %v", i)
+		}
+	}
+}
+
+// 与Test_AddConcurrent相同,多个goroutine同时加入不同的元素。
+// SetParallelism(64)启动64*GOMAXPROCS个goroutine
+func benchmarkAddContended(b *testing.B, s MapSet) {
+	b.SetParallelism(64)
+	b.RunParallel(func(pb *testing.PB) {
+		i := rand.Int()
+		for pb.Next() {
+			s.Add(i % N)
+			i++
+		}
+	})
+}
+
+func Benchmark_AddContendedThreadSafe(b *testing.B) {
+	benchmarkAddContended(b, NewMapSet())
+}
+
+func Benchmark_AddContendedSharded(b *testing.B) {
+	benchmarkAddContended(b, NewShardedSet(0))
+}
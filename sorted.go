@@ -0,0 +1,70 @@
+package mapSet
+
+import "cmp"
+
+// Comparator比较两个元素,a小于b时返回负数,相等时返回0,大于时返回正数
+type Comparator func(a, b interface{}) int
+
+// OrderedComparator返回按T的自然顺序比较元素的Comparator。
+// 不是T类型的元素不会panic,而是按compareElems的全序排列:先按类型,类型相同时再按值,
+// 因此混合了其他类型元素的SortedSet仍然有确定的顺序
+func OrderedComparator[T cmp.Ordered]() Comparator {
+	return func(a, b interface{}) int {
+		x, okA := a.(T)
+		y, okB := b.(T)
+		if okA && okB {
+			return cmp.Compare(x, y)
+		}
+		return compareElems(a, b)
+	}
+}
+
+// SortedSet是按Comparator保持元素有序的MapSet。
+// Each,ToSlice,String和JSON序列化都按从小到大的顺序进行,
+// 元素是否相同由Comparator判断。Comparator必须是集合中所有元素以及查询参数上的全序,
+// 比较器panic时SortedSet的方法也会panic。
+// 与其他MapSet一样,元素必须能用==比较,Freeze和与其他实现的运算都会用到元素的哈希,
+// 因此Add拒绝切片,map等不可比较的元素并返回false
+type SortedSet interface {
+	MapSet
+
+	// 返回最小的元素,集合为空时第二个返回值为false
+	Min() (interface{}, bool)
+
+	// 返回最大的元素,集合为空时第二个返回值为false
+	Max() (interface{}, bool)
+
+	// 返回小于等于x的最大元素,不存在时第二个返回值为false
+	Floor(x interface{}) (interface{}, bool)
+
+	// 返回大于等于x的最小元素,不存在时第二个返回值为false
+	Ceiling(x interface{}) (interface{}, bool)
+
+	// 按从小到大的顺序返回所有满足lo <= e <= hi的元素
+	Range(lo, hi interface{}) []interface{}
+
+	// 返回集合中小于x的元素个数
+	Rank(x interface{}) int
+
+	// 返回从小到大第i个元素(从0开始),i越界时第二个返回值为false
+	Select(i int) (interface{}, bool)
+}
+
+// NewSortedSet创建并返回一个线程安全的SortedSet,元素按cmp排序。
+// Add,Remove,Contains以及各种查询都是O(log n)
+func NewSortedSet(cmp Comparator, s ...interface{}) SortedSet {
+	set := newThreadSafeSortedSet(cmp)
+	for _, value := range s {
+		set.Add(value)
+	}
+	return &set
+}
+
+// NewThreadUnsafeSortedSet创建并返回一个线程不安全的SortedSet
+func NewThreadUnsafeSortedSet(cmp Comparator, s ...interface{}) SortedSet {
+	set := newThreadUnsafeSortedSet(cmp)
+	for _, value := range s {
+		set.Add(value)
+	}
+	return &set
+}
@@ -0,0 +1,273 @@
+package mapSet
+
+import (
+	"encoding/json"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func makeSortedSets(elems ...interface{}) []SortedSet {
+	cmp := OrderedComparator[int]()
+	return []SortedSet{NewSortedSet(cmp, elems...), NewThreadUnsafeSortedSet(cmp, elems...)}
+}
+
+func Test_SortedSetOrder(t *testing.T) {
+	for _, s := range makeSortedSets(5, 1, 4, 1, 3, 2) {
+		if s.RetElementCount() != 5 {
+			t.Errorf("%T should have 5 elements since 1 is a duplicate", s)
+		}
+		assertSliceEqual(s.ToSlice(), []interface{}{1, 2, 3, 4, 5}, t)
+
+		if s.String(",") != "Set{1,2,3,4,5}" {
+			t.Errorf("%T: unexpected string %s", s, s.String(","))
+		}
+
+		s.Remove(3)
+		s.Remove(42)
+		assertSliceEqual(s.ToSlice(), []interface{}{1, 2, 4, 5}, t)
+		if s.Contains(3) || !s.Contains(1, 2, 4, 5) {
+			t.Errorf("%T: unexpected membership after Remove", s)
+		}
+	}
+}
+
+func Test_SortedSetQueries(t *testing.T) {
+	for _, s := range makeSortedSets(10, 20, 30, 40) {
+		if v, ok := s.Min(); !ok || v != 10 {
+			t.Errorf("%T: Min should be 10, got %v", s, v)
+		}
+		if v, ok := s.Max(); !ok || v != 40 {
+			t.Errorf("%T: Max should be 40, got %v", s, v)
+		}
+		if v, ok := s.Floor(25); !ok || v != 20 {
+			t.Errorf("%T: Floor(25) should be 20, got %v", s, v)
+		}
+		if v, ok := s.Floor(30); !ok || v != 30 {
+			t.Errorf("%T: Floor(30) should be 30, got %v", s, v)
+		}
+		if _, ok := s.Floor(5); ok {
+			t.Errorf("%T: Floor(5) should not exist", s)
+		}
+		if v, ok := s.Ceiling(25); !ok || v != 30 {
+			t.Errorf("%T: Ceiling(25) should be 30, got %v", s, v)
+		}
+		if _, ok := s.Ceiling(41); ok {
+			t.Errorf("%T: Ceiling(41) should not exist", s)
+		}
+
+		assertSliceEqual(s.Range(15, 30), []interface{}{20, 30}, t)
+		assertSliceEqual(s.Range(30, 15), []interface{}{}, t)
+
+		if s.Rank(10) != 0 || s.Rank(25) != 2 || s.Rank(100) != 4 {
+			t.Errorf("%T: unexpected ranks", s)
+		}
+		if v, ok := s.Select(2); !ok || v != 30 {
+			t.Errorf("%T: Select(2) should be 30, got %v", s, v)
+		}
+		if _, ok := s.Select(4); ok {
+			t.Errorf("%T: Select(4) should be out of range", s)
+		}
+	}
+
+	for _, s := range makeSortedSets() {
+		if _, ok := s.Min(); ok {
+			t.Errorf("%T: Min of an empty set should not exist", s)
+		}
+		if _, ok := s.Max(); ok {
+			t.Errorf("%T: Max of an empty set should not exist", s)
+		}
+	}
+}
+
+func Test_SortedSetRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := NewThreadUnsafeSortedSet(OrderedComparator[int]())
+	expected := make(map[int]struct{})
+
+	for i := 0; i < 2000; i++ {
+		v := r.Intn(500)
+		if r.Intn(3) == 0 {
+			s.Remove(v)
+			delete(expected, v)
+		} else {
+			s.Add(v)
+			expected[v] = struct{}{}
+		}
+	}
+
+	sorted := make([]int, 0, len(expected))
+	for v := range expected {
+		sorted = append(sorted, v)
+	}
+	sort.Ints(sorted)
+
+	if s.RetElementCount() != len(sorted) {
+		t.Fatalf("expected %d elements, got %d", len(sorted), s.RetElementCount())
+	}
+	for i, v := range sorted {
+		if got, _ := s.Select(i); got != v {
+			t.Fatalf("Select(%d) should be %d, got %v", i, v, got)
+		}
+		if s.Rank(v) != i {
+			t.Fatalf("Rank(%d) should be %d, got %d", v, i, s.Rank(v))
+		}
+	}
+}
+
+func Test_SortedSetAlgebra(t *testing.T) {
+	for _, a := range makeSortedSets(5, 1, 3) {
+		b := makeUnsafeSet([]int{3, 4, 5})
+
+		assertSliceEqual(a.Union(b).ToSlice(), []interface{}{1, 3, 4, 5}, t)
+		assertSliceEqual(a.Intersect(b).ToSlice(), []interface{}{3, 5}, t)
+		assertSliceEqual(a.Difference(b).ToSlice(), []interface{}{1}, t)
+		assertSliceEqual(a.SymmetricDifference(b).ToSlice(), []interface{}{1, 4}, t)
+
+		if _, ok := a.Union(b).(SortedSet); !ok {
+			t.Errorf("%T: the union should keep the receiver's implementation", a)
+		}
+		if !a.Equal(makeSet([]int{1, 3, 5})) || !a.Intersect(b).IsProperSubset(a) {
+			t.Errorf("%T: predicates returned the wrong result", a)
+		}
+	}
+}
+
+func Test_SortedSetClone(t *testing.T) {
+	for _, a := range makeSortedSets(1, 2, 3) {
+		b := a.Clone()
+		b.Add(0)
+		b.Remove(3)
+
+		assertSliceEqual(a.ToSlice(), []interface{}{1, 2, 3}, t)
+		assertSliceEqual(b.ToSlice(), []interface{}{0, 1, 2}, t)
+	}
+}
+
+func Test_SortedSetRandom(t *testing.T) {
+	for _, s := range makeSortedSets(1, 2, 3, 4, 5) {
+		if !s.Contains(s.RandomReturn()) {
+			t.Errorf("%T: RandomReturn returned an element outside of the set", s)
+		}
+		if !NewThreadUnsafeSetFromSlice(s.Shuffled()).Equal(s) {
+			t.Errorf("%T: Shuffled should return every element", s)
+		}
+		if len(s.SampleWithReplacement(8)) != 8 {
+			t.Errorf("%T: SampleWithReplacement returned the wrong number of elements", s)
+		}
+
+		popped := s.PopN(2)
+		if len(popped) != 2 || s.RetElementCount() != 3 {
+			t.Errorf("%T: PopN should remove 2 elements", s)
+		}
+		for s.RetElementCount() > 0 {
+			s.Pop()
+		}
+		if s.Pop() != nil {
+			t.Errorf("%T: Pop on an empty set should return nil", s)
+		}
+	}
+}
+
+func Test_SortedSetRejectsUncomparable(t *testing.T) {
+	type holder struct{ V interface{} }
+	for _, s := range makeSortedSets(1, 2) {
+		if s.Add([]int{1}) || s.Add(holder{[]int{1}}) || s.AddAll(map[int]int{}, 3) != 1 {
+			t.Errorf("%T: Add should reject elements that are not comparable", s)
+		}
+		if s.RetElementCount() != 3 {
+			t.Errorf("%T: expected 3 elements, got %v", s, s.ToSlice())
+		}
+		if NewMapSet().Union(s).RetElementCount() != 3 || s.Freeze() != NewFrozenSet(1, 2, 3) {
+			t.Errorf("%T: a sorted set should work with hashing operations", s)
+		}
+	}
+}
+
+func Test_SortedSetJSON(t *testing.T) {
+	for _, s := range makeSortedSets(3, 1, 2) {
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `[1,2,3]` {
+			t.Errorf("%T: unexpected encoding %s", s, b)
+		}
+
+		c := NewSortedSet(OrderedComparator[int]())
+		if err := UnmarshalJSONWith(b, c, DecodeAs[int]()); err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(s, c, t)
+	}
+}
+
+func Test_SortedSetConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewSortedSet(OrderedComparator[int]())
+	ints := rand.Perm(N)
+
+	var wg sync.WaitGroup
+	wg.Add(len(ints) * 2)
+	for _, v := range ints {
+		go func(i int) {
+			s.Add(i)
+			wg.Done()
+		}(v)
+		go func(i int) {
+			s.Rank(i)
+			s.Floor(i)
+			wg.Done()
+		}(v)
+	}
+	wg.Wait()
+
+	for i := 0; i < N; i++ {
+		if v, _ := s.Select(i); v != i {
+			t.Fatalf("Select(%d) should be %d, got %v", i, i, v)
+		}
+	}
+}
+
+func Test_SortedSetMixedTypes(t *testing.T) {
+	intcmp := OrderedComparator[int]()
+	for _, s := range makeSortedSets(2, 1) {
+		if !s.Add("a") || !s.Add(1.5) || !s.Contains("a") || s.Contains("b") {
+			t.Errorf("%T: elements of other types should be ordered, not rejected", s)
+		}
+		// 不同类型按类型名排序:float64 < int < string
+		want := []interface{}{1.5, 1, 2, "a"}
+		if got := s.ToSlice(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[3] != want[3] {
+			t.Errorf("%T: expected %v, got %v", s, want, got)
+		}
+		if v, ok := s.Floor("b"); !ok || v != "a" {
+			t.Errorf("%T: Floor(\"b\") should be \"a\", got %v", s, v)
+		}
+		if !s.Equal(NewMapSet(1, 2, "a", 1.5)) || !s.Delete("a") || s.Rank(2) != 2 {
+			t.Errorf("%T: unexpected result with mixed element types", s)
+		}
+	}
+	if NewMapSet("a").Equal(NewThreadUnsafeSortedSet(intcmp, 1)) {
+		t.Error("sets of different element types should not be equal")
+	}
+	if u := NewSortedSet(intcmp, 1).Union(NewMapSet("a")); u.RetElementCount() != 2 || !u.Contains("a") {
+		t.Errorf("Union should keep elements of other types, got %v", u.ToSlice())
+	}
+
+	// 比较器自身的错误不会被吞掉
+	defer func() {
+		if recover() == nil {
+			t.Error("a panicking comparator should panic out of Add")
+		}
+	}()
+	broken := NewThreadUnsafeSortedSet(func(a, b interface{}) int {
+		if a == 13 || b == 13 {
+			panic("broken comparator")
+		}
+		return intcmp(a, b)
+	}, 0, 1, 2, 3, 4, 5, 6, 7)
+	broken.Add(13)
+}
@@ -0,0 +1,384 @@
+package mapSet
+
+import (
+	"iter"
+	"sync"
+)
+
+type threadSafeSortedSet struct {
+	s threadUnsafeSortedSet
+	sync.RWMutex
+}
+
+func newThreadSafeSortedSet(cmp Comparator) threadSafeSortedSet {
+	return threadSafeSortedSet{s: newThreadUnsafeSortedSet(cmp)}
+}
+
+// rlockOther与threadSafeSet.rlockOther相同,给调用者加读锁并返回锁内可以直接访问的other
+func (set *threadSafeSortedSet) rlockOther(other ReadOnlySet) (ReadOnlySet, func()) {
+	other = orEmpty(other)
+	if o, ok := other.(*threadSafeSortedSet); ok && o != nil {
+		rlockPair(set, o)
+		return &o.s, func() { runlockPair(set, o) }
+	}
+	if isThreadUnsafe(other) {
+		set.RLock()
+		return other, set.RUnlock
+	}
+	snapshot := NewThreadUnsafeSetFromSlice(other.ToSlice())
+	set.RLock()
+	return snapshot, set.RUnlock
+}
+
+func (set *threadSafeSortedSet) Add(i interface{}) bool {
+	set.Lock()
+	ret := set.s.Add(i)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) Contains(i ...interface{}) bool {
+	set.RLock()
+	ret := set.s.Contains(i...)
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) Clear() {
+	set.Lock()
+	set.s.Clear()
+	set.Unlock()
+}
+
+func (set *threadSafeSortedSet) setRandSource(r *randSource) {
+	set.Lock()
+	set.s.setRandSource(r)
+	set.Unlock()
+}
+
+func (set *threadSafeSortedSet) Remove(i interface{}) {
+	set.Lock()
+	set.s.Remove(i)
+	set.Unlock()
+}
+
+func (set *threadSafeSortedSet) Delete(i interface{}) bool {
+	set.Lock()
+	ret := set.s.Delete(i)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) AddAll(items ...interface{}) int {
+	set.Lock()
+	ret := set.s.AddAll(items...)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) RemoveAll(items ...interface{}) int {
+	set.Lock()
+	ret := set.s.RemoveAll(items...)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) ContainsAny(items ...interface{}) bool {
+	set.RLock()
+	ret := set.s.ContainsAny(items...)
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) RetElementCount() int {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.RetElementCount()
+}
+
+func (set *threadSafeSortedSet) Each(cb func(interface{}) bool) {
+	set.RLock()
+	set.s.Each(cb)
+	set.RUnlock()
+}
+
+func (set *threadSafeSortedSet) RemoveIf(pred func(interface{}) bool) int {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.RemoveIf(pred)
+}
+
+func (set *threadSafeSortedSet) RetainIf(pred func(interface{}) bool) int {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.RetainIf(pred)
+}
+
+func (set *threadSafeSortedSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	set.Lock()
+	defer set.Unlock()
+	set.s.EachMutable(cb)
+}
+
+func (set *threadSafeSortedSet) Min() (interface{}, bool) {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Min()
+}
+
+func (set *threadSafeSortedSet) Max() (interface{}, bool) {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Max()
+}
+
+func (set *threadSafeSortedSet) Floor(x interface{}) (interface{}, bool) {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Floor(x)
+}
+
+func (set *threadSafeSortedSet) Ceiling(x interface{}) (interface{}, bool) {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Ceiling(x)
+}
+
+func (set *threadSafeSortedSet) Range(lo, hi interface{}) []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Range(lo, hi)
+}
+
+func (set *threadSafeSortedSet) Rank(x interface{}) int {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Rank(x)
+}
+
+func (set *threadSafeSortedSet) Select(i int) (interface{}, bool) {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Select(i)
+}
+
+// lockOther与rlockOther相同,但给调用者加写锁,用于原地修改调用者的操作。
+// other就是调用者自身时只加一次写锁
+func (set *threadSafeSortedSet) lockOther(other ReadOnlySet) (ReadOnlySet, func()) {
+	other = orEmpty(other)
+	if o, ok := other.(*threadSafeSortedSet); ok && o != nil {
+		if o == set {
+			set.Lock()
+			return &set.s, set.Unlock
+		}
+		lockWithReader(set, o)
+		return &o.s, func() { unlockWithReader(set, o) }
+	}
+	if isThreadUnsafe(other) {
+		set.Lock()
+		return other, set.Unlock
+	}
+	snapshot := NewThreadUnsafeSetFromSlice(other.ToSlice())
+	set.Lock()
+	return snapshot, set.Unlock
+}
+
+func (set *threadSafeSortedSet) Equal(other ReadOnlySet) bool {
+	if set == nil {
+		return isNilSet(other) || other.RetElementCount() == 0
+	}
+
+	o, unlock := set.rlockOther(other)
+	ret := set.s.Equal(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) Union(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeUnion := set.s.Union(o).(*threadUnsafeSortedSet)
+	ret := &threadSafeSortedSet{s: *unsafeUnion}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) Intersect(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeIntersection := set.s.Intersect(o).(*threadUnsafeSortedSet)
+	ret := &threadSafeSortedSet{s: *unsafeIntersection}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) Difference(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeDifference := set.s.Difference(o).(*threadUnsafeSortedSet)
+	ret := &threadSafeSortedSet{s: *unsafeDifference}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) SymmetricDifference(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeDifference := set.s.SymmetricDifference(o).(*threadUnsafeSortedSet)
+	ret := &threadSafeSortedSet{s: *unsafeDifference}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) UnionWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.UnionWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) IntersectWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.IntersectWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) DifferenceWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.DifferenceWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.SymmetricDifferenceWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) IsSubset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsSubset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) IsProperSubset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsProperSubset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) IsSuperset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsSuperset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) IsProperSuperset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsProperSuperset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) IsDisjoint(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsDisjoint(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) Clone() MapSet {
+	set.RLock()
+
+	unsafeClone := set.s.Clone().(*threadUnsafeSortedSet)
+	ret := &threadSafeSortedSet{s: *unsafeClone}
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) Pop() interface{} {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.Pop()
+}
+
+func (set *threadSafeSortedSet) PopN(k int) []interface{} {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.PopN(k)
+}
+
+func (set *threadSafeSortedSet) String(sep string) string {
+	set.RLock()
+	ret := set.s.String(sep)
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) RandomReturn() interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.RandomReturn()
+}
+
+func (set *threadSafeSortedSet) Sample(k int) []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Sample(k)
+}
+
+func (set *threadSafeSortedSet) SampleWithReplacement(k int) []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.SampleWithReplacement(k)
+}
+
+func (set *threadSafeSortedSet) Shuffled() []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Shuffled()
+}
+
+func (set *threadSafeSortedSet) ToSlice() []interface{} {
+	set.RLock()
+	ret := set.s.ToSlice()
+	set.RUnlock()
+	return ret
+}
+
+func (set *threadSafeSortedSet) Freeze() FrozenSet {
+	return newFrozenSet(set.ToSlice())
+}
+
+func (set *threadSafeSortedSet) Iterator() *Iterator {
+	return newIterator(set.ToSlice())
+}
+
+func (set *threadSafeSortedSet) All() iter.Seq[interface{}] {
+	return snapshotSeq(set.ToSlice)
+}
+
+// MarshalJSON在读锁下把集合按从小到大的顺序编码为JSON数组
+func (set *threadSafeSortedSet) MarshalJSON() ([]byte, error) {
+	set.RLock()
+	b, err := set.s.MarshalJSON()
+	set.RUnlock()
+	return b, err
+}
+
+// UnmarshalJSON先在锁外解码JSON数组,再在写锁下把元素加入集合
+func (set *threadSafeSortedSet) UnmarshalJSON(b []byte) error {
+	elems, err := unmarshalJSONPrimitives(b)
+	if err != nil {
+		return err
+	}
+
+	set.Lock()
+	for _, elem := range elems {
+		set.s.Add(elem)
+	}
+	set.Unlock()
+	return nil
+}
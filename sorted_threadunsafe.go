@@ -0,0 +1,648 @@
+package mapSet
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// sortedNode是树堆(treap)的节点:按元素满足二叉搜索树的性质,按优先级满足堆的性质,
+// size记录子树的元素个数,用于Rank和Select
+type sortedNode struct {
+	elem        interface{}
+	prio        uint64
+	size        int
+	left, right *sortedNode
+}
+
+func (n *sortedNode) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *sortedNode) update() {
+	n.size = 1 + n.left.len() + n.right.len()
+}
+
+func (n *sortedNode) clone() *sortedNode {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.left = n.left.clone()
+	c.right = n.right.clone()
+	return &c
+}
+
+// mergeSorted合并两棵树,要求a中的元素都小于b中的元素
+func mergeSorted(a, b *sortedNode) *sortedNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.prio > b.prio {
+		a.right = mergeSorted(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = mergeSorted(a, b.left)
+	b.update()
+	return b
+}
+
+type threadUnsafeSortedSet struct {
+	root *sortedNode
+	cmp  Comparator
+	// seq用于生成节点的优先级,打散后的优先级保证树的期望高度为O(log n)
+	seq uint64
+	// rnd是SetRandSource设置的随机数源,Clear和Clone都保留它
+	rnd *randSource
+}
+
+func newThreadUnsafeSortedSet(cmp Comparator) threadUnsafeSortedSet {
+	return threadUnsafeSortedSet{cmp: cmp}
+}
+
+// split把以n为根的树拆成两棵:左边的元素都小于x(orEqual为true时为小于等于x),右边为其余元素
+func (set *threadUnsafeSortedSet) split(n *sortedNode, x interface{}, orEqual bool) (*sortedNode, *sortedNode) {
+	if n == nil {
+		return nil, nil
+	}
+	c := set.cmp(n.elem, x)
+	if c < 0 || (orEqual && c == 0) {
+		l, r := set.split(n.right, x, orEqual)
+		n.right = l
+		n.update()
+		return n, r
+	}
+	l, r := set.split(n.left, x, orEqual)
+	n.left = r
+	n.update()
+	return l, n
+}
+
+func (set *threadUnsafeSortedSet) find(x interface{}) *sortedNode {
+
+	n := set.root
+	for n != nil {
+		c := set.cmp(x, n.elem)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+func (set *threadUnsafeSortedSet) Add(i interface{}) bool {
+	if !isComparableElem(i) || set.find(i) != nil {
+		return false //False if it existed already
+	}
+
+	set.seq++
+	node := &sortedNode{elem: i, prio: mix64(set.seq), size: 1}
+	l, r := set.split(set.root, i, false)
+	set.root = mergeSorted(mergeSorted(l, node), r)
+	return true
+}
+
+func (set *threadUnsafeSortedSet) Contains(i ...interface{}) bool {
+	for _, val := range i {
+		if set.find(val) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *threadUnsafeSortedSet) Clear() {
+	set.root = nil
+}
+
+func (set *threadUnsafeSortedSet) setRandSource(r *randSource) {
+	set.rnd = r
+}
+
+func (set *threadUnsafeSortedSet) Remove(i interface{}) {
+	if set.find(i) == nil {
+		return
+	}
+	l, r := set.split(set.root, i, false)
+	_, r = set.split(r, i, true)
+	set.root = mergeSorted(l, r)
+}
+
+func (set *threadUnsafeSortedSet) Delete(i interface{}) bool {
+	if set.find(i) == nil {
+		return false
+	}
+	set.Remove(i)
+	return true
+}
+
+func (set *threadUnsafeSortedSet) AddAll(items ...interface{}) int {
+	added := 0
+	for _, item := range items {
+		if set.Add(item) {
+			added++
+		}
+	}
+	return added
+}
+
+func (set *threadUnsafeSortedSet) RemoveAll(items ...interface{}) int {
+	removed := 0
+	for _, item := range items {
+		if set.Delete(item) {
+			removed++
+		}
+	}
+	return removed
+}
+
+func (set *threadUnsafeSortedSet) ContainsAny(items ...interface{}) bool {
+	for _, item := range items {
+		if set.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *threadUnsafeSortedSet) RetElementCount() int {
+	return set.root.len()
+}
+
+func (set *threadUnsafeSortedSet) Each(cb func(interface{}) bool) {
+	var stack []*sortedNode
+	n := set.root
+	for n != nil || len(stack) > 0 {
+		for n != nil {
+			stack = append(stack, n)
+			n = n.left
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cb(n.elem) {
+			return
+		}
+		n = n.right
+	}
+}
+
+func (set *threadUnsafeSortedSet) RemoveIf(pred func(interface{}) bool) int {
+	// 遍历时不能修改树,先收集再删除
+	var matched []interface{}
+	set.Each(func(elem interface{}) bool {
+		if pred(elem) {
+			matched = append(matched, elem)
+		}
+		return false
+	})
+	for _, elem := range matched {
+		set.Remove(elem)
+	}
+	return len(matched)
+}
+
+func (set *threadUnsafeSortedSet) RetainIf(pred func(interface{}) bool) int {
+	return set.RemoveIf(func(elem interface{}) bool {
+		return !pred(elem)
+	})
+}
+
+func (set *threadUnsafeSortedSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	eachMutable(set, cb)
+}
+
+func (set *threadUnsafeSortedSet) Min() (interface{}, bool) {
+	n := set.root
+	if n == nil {
+		return nil, false
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.elem, true
+}
+
+func (set *threadUnsafeSortedSet) Max() (interface{}, bool) {
+	n := set.root
+	if n == nil {
+		return nil, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.elem, true
+}
+
+func (set *threadUnsafeSortedSet) Floor(x interface{}) (interface{}, bool) {
+	var best *sortedNode
+	n := set.root
+	for n != nil {
+		if set.cmp(n.elem, x) <= 0 {
+			best = n
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	return best.elem, true
+}
+
+func (set *threadUnsafeSortedSet) Ceiling(x interface{}) (interface{}, bool) {
+	var best *sortedNode
+	n := set.root
+	for n != nil {
+		if set.cmp(n.elem, x) >= 0 {
+			best = n
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	return best.elem, true
+}
+
+func (set *threadUnsafeSortedSet) Range(lo, hi interface{}) []interface{} {
+	ret := make([]interface{}, 0)
+	var walk func(n *sortedNode)
+	walk = func(n *sortedNode) {
+		if n == nil {
+			return
+		}
+		cl, ch := set.cmp(n.elem, lo), set.cmp(n.elem, hi)
+		if cl > 0 {
+			walk(n.left)
+		}
+		if cl >= 0 && ch <= 0 {
+			ret = append(ret, n.elem)
+		}
+		if ch < 0 {
+			walk(n.right)
+		}
+	}
+	walk(set.root)
+	return ret
+}
+
+func (set *threadUnsafeSortedSet) Rank(x interface{}) int {
+	rank := 0
+	n := set.root
+	for n != nil {
+		if set.cmp(n.elem, x) < 0 {
+			rank += n.left.len() + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return rank
+}
+
+func (set *threadUnsafeSortedSet) Select(i int) (interface{}, bool) {
+	if i < 0 || i >= set.root.len() {
+		return nil, false
+	}
+	n := set.root
+	for {
+		l := n.left.len()
+		switch {
+		case i < l:
+			n = n.left
+		case i == l:
+			return n.elem, true
+		default:
+			i -= l + 1
+			n = n.right
+		}
+	}
+}
+
+func (set *threadUnsafeSortedSet) Pop() interface{} {
+	if set.RetElementCount() == 0 {
+		return nil
+	}
+	item := set.RandomReturn()
+	set.Remove(item)
+	return item
+}
+
+func (set *threadUnsafeSortedSet) PopN(k int) []interface{} {
+	items := set.Sample(k)
+	for _, item := range items {
+		set.Remove(item)
+	}
+	return items
+}
+
+func (set *threadUnsafeSortedSet) Equal(other ReadOnlySet) bool {
+	if set == nil {
+		return isNilSet(other) || other.RetElementCount() == 0
+	}
+	other = orEmpty(other)
+
+	if set.RetElementCount() != other.RetElementCount() {
+		return false
+	}
+	ret := true
+	set.Each(func(elem interface{}) bool {
+		if !other.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+// emptyLike返回一个与set使用相同Comparator的空集合
+func (set *threadUnsafeSortedSet) emptyLike() threadUnsafeSortedSet {
+	return newThreadUnsafeSortedSet(set.cmp)
+}
+
+func (set *threadUnsafeSortedSet) Union(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	unionedSet := threadUnsafeSortedSet{root: set.root.clone(), cmp: set.cmp, seq: set.seq}
+	other.Each(func(elem interface{}) bool {
+		unionedSet.Add(elem)
+		return false
+	})
+	return &unionedSet
+}
+
+func (set *threadUnsafeSortedSet) Intersect(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	intersection := set.emptyLike()
+	set.Each(func(elem interface{}) bool {
+		if other.Contains(elem) {
+			intersection.Add(elem)
+		}
+		return false
+	})
+	return &intersection
+}
+
+func (set *threadUnsafeSortedSet) Difference(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	difference := set.emptyLike()
+	set.Each(func(elem interface{}) bool {
+		if !other.Contains(elem) {
+			difference.Add(elem)
+		}
+		return false
+	})
+	return &difference
+}
+
+func (set *threadUnsafeSortedSet) SymmetricDifference(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	sd := set.emptyLike()
+	set.Each(func(elem interface{}) bool {
+		if !other.Contains(elem) {
+			sd.Add(elem)
+		}
+		return false
+	})
+	other.Each(func(elem interface{}) bool {
+		if !set.Contains(elem) {
+			sd.Add(elem)
+		}
+		return false
+	})
+	return &sd
+}
+
+func (set *threadUnsafeSortedSet) UnionWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		return 0
+	}
+
+	added := 0
+	other.Each(func(elem interface{}) bool {
+		if set.Add(elem) {
+			added++
+		}
+		return false
+	})
+	return added
+}
+
+func (set *threadUnsafeSortedSet) IntersectWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		return 0
+	}
+
+	return set.RemoveIf(func(elem interface{}) bool {
+		return !other.Contains(elem)
+	})
+}
+
+func (set *threadUnsafeSortedSet) DifferenceWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		removed := set.RetElementCount()
+		set.Clear()
+		return removed
+	}
+
+	return set.RemoveIf(func(elem interface{}) bool {
+		return other.Contains(elem)
+	})
+}
+
+func (set *threadUnsafeSortedSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		return set.DifferenceWith(other)
+	}
+
+	changed := 0
+	other.Each(func(elem interface{}) bool {
+		if !set.Delete(elem) {
+			set.Add(elem)
+		}
+		changed++
+		return false
+	})
+	return changed
+}
+
+func (set *threadUnsafeSortedSet) IsSubset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if set.RetElementCount() > other.RetElementCount() {
+		return false
+	}
+	ret := true
+	set.Each(func(elem interface{}) bool {
+		if !other.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+func (set *threadUnsafeSortedSet) IsProperSubset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	return set.RetElementCount() < other.RetElementCount() && set.IsSubset(other)
+}
+
+func (set *threadUnsafeSortedSet) IsSuperset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if set.RetElementCount() < other.RetElementCount() {
+		return false
+	}
+	ret := true
+	other.Each(func(elem interface{}) bool {
+		if !set.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+func (set *threadUnsafeSortedSet) IsProperSuperset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	return set.RetElementCount() > other.RetElementCount() && set.IsSuperset(other)
+}
+
+func (set *threadUnsafeSortedSet) IsDisjoint(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	ret := true
+	set.Each(func(elem interface{}) bool {
+		if other.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+func (set *threadUnsafeSortedSet) Clone() MapSet {
+	return &threadUnsafeSortedSet{root: set.root.clone(), cmp: set.cmp, seq: set.seq, rnd: set.rnd}
+}
+
+func (set *threadUnsafeSortedSet) String(sep string) string {
+	items := make([]string, 0, set.RetElementCount())
+
+	set.Each(func(elem interface{}) bool {
+		items = append(items, fmt.Sprintf("%v", elem))
+		return false
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, sep))
+}
+
+// 有序集合按随机下标用Select取元素,结果是均匀的,并且在随机数源种子固定时可以复现
+func (set *threadUnsafeSortedSet) RandomReturn() interface{} {
+	n := set.RetElementCount()
+	if n == 0 {
+		return nil
+	}
+	item, _ := set.Select(set.rnd.intn(n))
+	return item
+}
+
+func (set *threadUnsafeSortedSet) Sample(k int) []interface{} {
+	n := set.RetElementCount()
+	if k > n {
+		k = n
+	}
+	if k <= 0 {
+		return []interface{}{}
+	}
+	items := make([]interface{}, k)
+	for i, idx := range set.rnd.perm(n)[:k] {
+		items[i], _ = set.Select(idx)
+	}
+	return items
+}
+
+func (set *threadUnsafeSortedSet) SampleWithReplacement(k int) []interface{} {
+	n := set.RetElementCount()
+	if k <= 0 || n == 0 {
+		return []interface{}{}
+	}
+	items := make([]interface{}, k)
+	for i := range items {
+		items[i], _ = set.Select(set.rnd.intn(n))
+	}
+	return items
+}
+
+func (set *threadUnsafeSortedSet) Shuffled() []interface{} {
+	sorted := set.ToSlice()
+	items := make([]interface{}, len(sorted))
+	for i, idx := range set.rnd.perm(len(sorted)) {
+		items[i] = sorted[idx]
+	}
+	return items
+}
+
+func (set *threadUnsafeSortedSet) ToSlice() []interface{} {
+	keys := make([]interface{}, 0, set.RetElementCount())
+	set.Each(func(elem interface{}) bool {
+		keys = append(keys, elem)
+		return false
+	})
+
+	return keys
+}
+
+func (set *threadUnsafeSortedSet) Freeze() FrozenSet {
+	return newFrozenSet(set.ToSlice())
+}
+
+func (set *threadUnsafeSortedSet) Iterator() *Iterator {
+	return newIterator(set.ToSlice())
+}
+
+func (set *threadUnsafeSortedSet) All() iter.Seq[interface{}] {
+	return snapshotSeq(set.ToSlice)
+}
+
+// MarshalJSON creates a JSON array from the set in sorted order
+func (set *threadUnsafeSortedSet) MarshalJSON() ([]byte, error) {
+	return marshalJSONElems(set.ToSlice())
+}
+
+// UnmarshalJSON adds the primitive elements of a JSON array to the set.
+// Numbers are decoded as json.Number, so the comparator must accept them;
+// use UnmarshalJSONWith with DecodeAs to decode into the comparator's type.
+func (set *threadUnsafeSortedSet) UnmarshalJSON(b []byte) error {
+	elems, err := unmarshalJSONPrimitives(b)
+	if err != nil {
+		return err
+	}
+
+	for _, elem := range elems {
+		set.Add(elem)
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package mapSet
 
 import (
+	"iter"
 	"sync"
+	"unsafe"
 )
 
 type threadSafeSet struct {
@@ -29,41 +31,292 @@ func (set *threadSafeSet) Contains(i ...interface{}) bool {
 
 func (set *threadSafeSet) Clear() {
 	set.Lock()
-	set.s = newThreadUnsafeSet()
+	set.s.Clear()
+	set.Unlock()
+}
+
+func (set *threadSafeSet) setRandSource(r *randSource) {
+	set.Lock()
+	set.s.setRandSource(r)
 	set.Unlock()
 }
 
 func (set *threadSafeSet) Remove(i interface{}) {
 	set.Lock()
-	delete(set.s, i)
+	set.s.Remove(i)
+	set.Unlock()
+}
+
+func (set *threadSafeSet) Delete(i interface{}) bool {
+	set.Lock()
+	ret := set.s.Delete(i)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeSet) AddAll(items ...interface{}) int {
+	set.Lock()
+	ret := set.s.AddAll(items...)
 	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeSet) RemoveAll(items ...interface{}) int {
+	set.Lock()
+	ret := set.s.RemoveAll(items...)
+	set.Unlock()
+	return ret
+}
+
+func (set *threadSafeSet) ContainsAny(items ...interface{}) bool {
+	set.RLock()
+	ret := set.s.ContainsAny(items...)
+	set.RUnlock()
+	return ret
 }
 
 func (set *threadSafeSet) RetElementCount() int {
 	set.RLock()
 	defer set.RUnlock()
-	return len(set.s)
+	return set.s.RetElementCount()
 }
 
 func (set *threadSafeSet) Each(cb func(interface{}) bool) {
 	set.RLock()
-	for elem := range set.s {
-		if cb(elem) {
-			break
-		}
-	}
+	set.s.Each(cb)
 	set.RUnlock()
 }
 
-func (set *threadSafeSet) Equal(other MapSet) bool {
-	o := other.(*threadSafeSet)
+func (set *threadSafeSet) RemoveIf(pred func(interface{}) bool) int {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.RemoveIf(pred)
+}
+
+func (set *threadSafeSet) RetainIf(pred func(interface{}) bool) int {
+	set.Lock()
+	defer set.Unlock()
+	return set.s.RetainIf(pred)
+}
 
+func (set *threadSafeSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	set.Lock()
+	defer set.Unlock()
+	set.s.EachMutable(cb)
+}
+
+// rlockPair按内存地址的固定顺序给两个集合加读锁,
+// 避免a.Union(b)与b.Union(a)同时执行且有写者等待时互相死锁。
+// 所有带锁的实现共用这一函数
+func rlockPair[S any, P interface {
+	*S
+	RLock()
+	RUnlock()
+}](a, b P) {
+	if a == b {
+		a.RLock()
+		return
+	}
+	if uintptr(unsafe.Pointer(a)) < uintptr(unsafe.Pointer(b)) {
+		a.RLock()
+		b.RLock()
+	} else {
+		b.RLock()
+		a.RLock()
+	}
+}
+
+func runlockPair[S any, P interface {
+	*S
+	RLock()
+	RUnlock()
+}](a, b P) {
+	a.RUnlock()
+	if a != b {
+		b.RUnlock()
+	}
+}
+
+// lockWithReader给w加写锁,给r加读锁,与rlockPair一样按内存地址的固定顺序加锁,
+// 避免a.UnionWith(b)与b.UnionWith(a)同时执行时死锁。要求w与r不是同一个集合
+func lockWithReader[S any, P interface {
+	*S
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}](w, r P) {
+	if uintptr(unsafe.Pointer(w)) < uintptr(unsafe.Pointer(r)) {
+		w.Lock()
+		r.RLock()
+	} else {
+		r.RLock()
+		w.Lock()
+	}
+}
+
+func unlockWithReader[S any, P interface {
+	*S
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}](w, r P) {
+	w.Unlock()
+	r.RUnlock()
+}
+
+// rlockOther给调用者加读锁,并返回在锁内可以直接访问的other以及对应的解锁函数。
+// other同为threadSafeSet时按顺序同时加锁;other为其他带锁的实现时,
+// 先在不持有本集合锁的情况下对其取快照,避免两把锁交叉持有
+func (set *threadSafeSet) rlockOther(other ReadOnlySet) (ReadOnlySet, func()) {
+	other = orEmpty(other)
+	if o, ok := other.(*threadSafeSet); ok && o != nil {
+		rlockPair(set, o)
+		return &o.s, func() { runlockPair(set, o) }
+	}
+	if isThreadUnsafe(other) {
+		set.RLock()
+		return other, set.RUnlock
+	}
+	snapshot := NewThreadUnsafeSetFromSlice(other.ToSlice())
 	set.RLock()
-	o.RLock()
+	return snapshot, set.RUnlock
+}
 
-	ret := set.s.Equal(&o.s)
-	set.RUnlock()
-	o.RUnlock()
+// isThreadUnsafe判断other是否为不带锁的非nil实现,这类集合可以在持有本集合锁时直接访问
+func isThreadUnsafe(other ReadOnlySet) bool {
+	switch other.(type) {
+	case *threadUnsafeSet, *threadUnsafeOrderedSet, *threadUnsafeSortedSet:
+		return !isNilSet(other)
+	}
+	return false
+}
+
+// lockOther与rlockOther相同,但给调用者加写锁,用于原地修改调用者的操作。
+// other就是调用者自身时只加一次写锁
+func (set *threadSafeSet) lockOther(other ReadOnlySet) (ReadOnlySet, func()) {
+	other = orEmpty(other)
+	if o, ok := other.(*threadSafeSet); ok && o != nil {
+		if o == set {
+			set.Lock()
+			return &set.s, set.Unlock
+		}
+		lockWithReader(set, o)
+		return &o.s, func() { unlockWithReader(set, o) }
+	}
+	if isThreadUnsafe(other) {
+		set.Lock()
+		return other, set.Unlock
+	}
+	snapshot := NewThreadUnsafeSetFromSlice(other.ToSlice())
+	set.Lock()
+	return snapshot, set.Unlock
+}
+
+func (set *threadSafeSet) Equal(other ReadOnlySet) bool {
+	if set == nil {
+		return isNilSet(other) || other.RetElementCount() == 0
+	}
+
+	o, unlock := set.rlockOther(other)
+	ret := set.s.Equal(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) Union(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeUnion := set.s.Union(o).(*threadUnsafeSet)
+	ret := &threadSafeSet{s: *unsafeUnion}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) Intersect(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeIntersection := set.s.Intersect(o).(*threadUnsafeSet)
+	ret := &threadSafeSet{s: *unsafeIntersection}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) Difference(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeDifference := set.s.Difference(o).(*threadUnsafeSet)
+	ret := &threadSafeSet{s: *unsafeDifference}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) SymmetricDifference(other ReadOnlySet) MapSet {
+	o, unlock := set.rlockOther(other)
+	unsafeDifference := set.s.SymmetricDifference(o).(*threadUnsafeSet)
+	ret := &threadSafeSet{s: *unsafeDifference}
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) UnionWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.UnionWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) IntersectWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.IntersectWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) DifferenceWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.DifferenceWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	o, unlock := set.lockOther(other)
+	ret := set.s.SymmetricDifferenceWith(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) IsSubset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsSubset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) IsProperSubset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsProperSubset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) IsSuperset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsSuperset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) IsProperSuperset(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsProperSuperset(o)
+	unlock()
+	return ret
+}
+
+func (set *threadSafeSet) IsDisjoint(other ReadOnlySet) bool {
+	o, unlock := set.rlockOther(other)
+	ret := set.s.IsDisjoint(o)
+	unlock()
 	return ret
 }
 
@@ -91,17 +344,71 @@ func (set *threadSafeSet) String(sep string) string {
 }
 
 func (set *threadSafeSet) RandomReturn() interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.RandomReturn()
+}
+
+func (set *threadSafeSet) Sample(k int) []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Sample(k)
+}
+
+func (set *threadSafeSet) SampleWithReplacement(k int) []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.SampleWithReplacement(k)
+}
+
+func (set *threadSafeSet) PopN(k int) []interface{} {
 	set.Lock()
 	defer set.Unlock()
-	return set.s.RandomReturn()
+	return set.s.PopN(k)
+}
+
+func (set *threadSafeSet) Shuffled() []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.s.Shuffled()
 }
 
 func (set *threadSafeSet) ToSlice() []interface{} {
-	keys := make([]interface{}, 0, set.RetElementCount())
 	set.RLock()
-	for elem := range set.s {
-		keys = append(keys, elem)
-	}
+	keys := set.s.ToSlice()
 	set.RUnlock()
 	return keys
 }
+
+func (set *threadSafeSet) Freeze() FrozenSet {
+	return newFrozenSet(set.ToSlice())
+}
+
+func (set *threadSafeSet) Iterator() *Iterator {
+	return newIterator(set.ToSlice())
+}
+
+func (set *threadSafeSet) All() iter.Seq[interface{}] {
+	return snapshotSeq(set.ToSlice)
+}
+
+// MarshalJSON在读锁下把集合编码为JSON数组
+func (set *threadSafeSet) MarshalJSON() ([]byte, error) {
+	set.RLock()
+	b, err := set.s.MarshalJSON()
+	set.RUnlock()
+	return b, err
+}
+
+// UnmarshalJSON先在锁外解码JSON数组,再在写锁下把元素加入集合
+func (set *threadSafeSet) UnmarshalJSON(b []byte) error {
+	decoded := newThreadUnsafeSet()
+	if err := decoded.UnmarshalJSON(b); err != nil {
+		return err
+	}
+
+	set.Lock()
+	set.s.UnionWith(&decoded)
+	set.Unlock()
+	return nil
+}
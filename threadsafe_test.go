@@ -1,6 +1,7 @@
 package mapSet
 
 import (
+	"encoding/json"
 	"math/rand"
 	"runtime"
 	"sync"
@@ -33,6 +34,28 @@ func Test_AddConcurrent(t *testing.T) {
 	}
 }
 
+func Test_AddAllConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewMapSet()
+	ints := rand.Perm(N)
+
+	var added int64
+	var wg sync.WaitGroup
+	wg.Add(len(ints))
+	for i := 0; i < len(ints); i++ {
+		go func(i int) {
+			atomic.AddInt64(&added, int64(s.AddAll(i, (i+1)%N)))
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	if added != N || s.RetElementCount() != N {
+		t.Errorf("expected %d elements added, got %d (cardinality %d)", N, added, s.RetElementCount())
+	}
+}
+
 func Test_CardinalityConcurrent(t *testing.T) {
 	runtime.GOMAXPROCS(2)
 
@@ -144,6 +167,125 @@ func Test_EqualConcurrent(t *testing.T) {
 	wg.Wait()
 }
 
+func Test_UnionConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss := NewMapSet(), NewMapSet()
+	ints := rand.Perm(N)
+	for _, v := range ints {
+		s.Add(v)
+		ss.Add(v)
+	}
+
+	var wg sync.WaitGroup
+	for _, v := range ints {
+		wg.Add(3)
+		go func() {
+			s.Union(ss)
+			wg.Done()
+		}()
+		go func() {
+			ss.Union(s)
+			wg.Done()
+		}()
+		go func(i int) {
+			s.Add(i + N)
+			ss.Add(i + N)
+			wg.Done()
+		}(v)
+	}
+	wg.Wait()
+}
+
+func Test_UnionWithConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss := NewMapSet(), NewMapSet()
+	ints := rand.Perm(N)
+
+	var wg sync.WaitGroup
+	for _, v := range ints {
+		wg.Add(4)
+		go func(i int) {
+			s.Add(i)
+			wg.Done()
+		}(v)
+		go func(i int) {
+			ss.Add(i + N)
+			wg.Done()
+		}(v)
+		go func() {
+			s.UnionWith(ss)
+			wg.Done()
+		}()
+		go func() {
+			ss.UnionWith(s)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	s.UnionWith(ss)
+	ss.UnionWith(s)
+	if s.RetElementCount() != 2*N || !s.Equal(ss) {
+		t.Errorf("expected both sets to hold %d elements, got %d and %d", 2*N, s.RetElementCount(), ss.RetElementCount())
+	}
+}
+
+func Test_IntersectConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss := NewMapSet(), NewMapSet()
+	ints := rand.Perm(N)
+	for _, v := range ints {
+		s.Add(v)
+		ss.Add(v)
+	}
+
+	var wg sync.WaitGroup
+	for range ints {
+		wg.Add(2)
+		go func() {
+			s.Intersect(ss)
+			wg.Done()
+		}()
+		go func() {
+			ss.Intersect(s)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_IsSubsetConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss := NewMapSet(), NewMapSet()
+	ints := rand.Perm(N)
+	for _, v := range ints {
+		s.Add(v)
+		ss.Add(v)
+	}
+
+	var wg sync.WaitGroup
+	for _, v := range ints {
+		wg.Add(3)
+		go func() {
+			s.IsSubset(ss)
+			wg.Done()
+		}()
+		go func() {
+			ss.IsSuperset(s)
+			wg.Done()
+		}()
+		go func(i int) {
+			s.Remove(i)
+			wg.Done()
+		}(v)
+	}
+	wg.Wait()
+}
+
 func Test_EachConcurrent(t *testing.T) {
 	runtime.GOMAXPROCS(2)
 	concurrent := 10
@@ -173,6 +315,65 @@ func Test_EachConcurrent(t *testing.T) {
 	}
 }
 
+func Test_RandomReturnConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewMapSet()
+	ints := rand.Perm(N)
+	for _, v := range ints {
+		s.Add(v)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(ints) * 2)
+	for _, v := range ints {
+		go func() {
+			if s.RandomReturn() == nil {
+				t.Error("RandomReturn should not return nil on a non-empty set")
+			}
+			wg.Done()
+		}()
+		go func(i int) {
+			s.Add(i + N)
+			wg.Done()
+		}(v)
+	}
+	wg.Wait()
+}
+
+func Test_RemoveIfConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewMapSet()
+	ints := rand.Perm(N)
+	for _, v := range ints {
+		s.Add(v)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(ints) * 2)
+	for _, v := range ints {
+		go func(i int) {
+			s.RemoveIf(func(elem interface{}) bool {
+				return elem.(int) == i
+			})
+			wg.Done()
+		}(v)
+		go func(i int) {
+			s.EachMutable(func(elem interface{}, set MapSet) bool {
+				set.Remove(i + N)
+				return true
+			})
+			wg.Done()
+		}(v)
+	}
+	wg.Wait()
+
+	if s.RetElementCount() != 0 {
+		t.Errorf("Expected cardinality 0; got %v", s.RetElementCount())
+	}
+}
+
 func Test_RemoveConcurrent(t *testing.T) {
 	runtime.GOMAXPROCS(2)
 
@@ -217,6 +418,29 @@ func Test_StringConcurrent(t *testing.T) {
 	wg.Wait()
 }
 
+func Test_MarshalJSONConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewMapSet()
+	ints := rand.Perm(N)
+
+	var wg sync.WaitGroup
+	wg.Add(len(ints) * 2)
+	for _, v := range ints {
+		go func(i int) {
+			s.Add(i)
+			wg.Done()
+		}(v)
+		go func() {
+			if _, err := json.Marshal(s); err != nil {
+				t.Error(err)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
 func Test_ToSlice(t *testing.T) {
 	runtime.GOMAXPROCS(2)
 
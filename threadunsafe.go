@@ -4,28 +4,41 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"strings"
 )
 
-type threadUnsafeSet map[interface{}]struct{}
+// threadUnsafeSet用map保存每个元素在items中的下标,items紧密地保存所有元素,
+// 使RandomReturn和Pop只需要O(1)时间。删除元素时把items的最后一个元素移到空出的位置。
+// 零值是一个可用的空集合
+type threadUnsafeSet struct {
+	m     map[interface{}]int
+	items []interface{}
+	// rnd是SetRandSource设置的随机数源,Clear和Clone都保留它
+	rnd *randSource
+}
 
 func newThreadUnsafeSet() threadUnsafeSet {
-	return make(threadUnsafeSet)
+	return threadUnsafeSet{m: make(map[interface{}]int)}
 }
 
 func (set *threadUnsafeSet) Add(i interface{}) bool {
-	_, found := (*set)[i]
+	_, found := set.m[i]
 	if found {
 		return false //False if it existed already
 	}
 
-	(*set)[i] = struct{}{}
+	if set.m == nil {
+		set.m = make(map[interface{}]int)
+	}
+	set.m[i] = len(set.items)
+	set.items = append(set.items, i)
 	return true
 }
 
 func (set *threadUnsafeSet) Contains(i ...interface{}) bool {
 	for _, val := range i {
-		if _, ok := (*set)[val]; !ok {
+		if _, ok := set.m[val]; !ok {
 			return false
 		}
 	}
@@ -33,40 +46,250 @@ func (set *threadUnsafeSet) Contains(i ...interface{}) bool {
 }
 
 func (set *threadUnsafeSet) Clear() {
-	*set = newThreadUnsafeSet()
+	*set = threadUnsafeSet{m: make(map[interface{}]int), rnd: set.rnd}
+}
+
+func (set *threadUnsafeSet) setRandSource(r *randSource) {
+	set.rnd = r
 }
 
 func (set *threadUnsafeSet) Remove(i interface{})  {
-	delete(*set, i)
+	set.Delete(i)
+}
+
+func (set *threadUnsafeSet) Delete(i interface{}) bool {
+	idx, ok := set.m[i]
+	if !ok {
+		return false
+	}
+	last := len(set.items) - 1
+	if idx != last {
+		set.items[idx] = set.items[last]
+		set.m[set.items[idx]] = idx
+	}
+	set.items[last] = nil
+	set.items = set.items[:last]
+	delete(set.m, i)
+	return true
+}
+
+func (set *threadUnsafeSet) AddAll(items ...interface{}) int {
+	added := 0
+	for _, item := range items {
+		if set.Add(item) {
+			added++
+		}
+	}
+	return added
+}
+
+func (set *threadUnsafeSet) RemoveAll(items ...interface{}) int {
+	removed := 0
+	for _, item := range items {
+		if set.Delete(item) {
+			removed++
+		}
+	}
+	return removed
+}
+
+func (set *threadUnsafeSet) ContainsAny(items ...interface{}) bool {
+	for _, item := range items {
+		if set.Contains(item) {
+			return true
+		}
+	}
+	return false
 }
 
 func (set *threadUnsafeSet) RetElementCount() int {
-	return len(*set)
+	return len(set.items)
 }
 
 func (set *threadUnsafeSet) Each(cb func(interface{}) bool) {
-	for elem := range *set {
+	for elem := range set.m {
 		if cb(elem) {
 			break
 		}
 	}
 }
 
+func (set *threadUnsafeSet) RemoveIf(pred func(interface{}) bool) int {
+	removed := 0
+	for elem := range set.m {
+		if pred(elem) {
+			set.Delete(elem)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (set *threadUnsafeSet) RetainIf(pred func(interface{}) bool) int {
+	return set.RemoveIf(func(elem interface{}) bool {
+		return !pred(elem)
+	})
+}
+
+func (set *threadUnsafeSet) EachMutable(cb func(elem interface{}, set MapSet) bool) {
+	eachMutable(set, cb)
+}
+
 func (set *threadUnsafeSet) Pop() interface{} {
-	for item := range *set {
-		delete(*set, item)
-		return item
+	if len(set.items) == 0 {
+		return nil
 	}
-	return nil
+	item := set.items[set.rnd.intn(len(set.items))]
+	set.Delete(item)
+	return item
 }
 
-func (set *threadUnsafeSet) Equal(other MapSet) bool {
-	_ = other.(*threadUnsafeSet)
+func (set *threadUnsafeSet) Equal(other ReadOnlySet) bool {
+	if set == nil {
+		return isNilSet(other) || other.RetElementCount() == 0
+	}
+	other = orEmpty(other)
 
 	if set.RetElementCount() != other.RetElementCount() {
 		return false
 	}
-	for elem := range *set {
+	for elem := range set.m {
+		if !other.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *threadUnsafeSet) Union(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	unionedSet := newThreadUnsafeSet()
+	for elem := range set.m {
+		unionedSet.Add(elem)
+	}
+	other.Each(func(elem interface{}) bool {
+		unionedSet.Add(elem)
+		return false
+	})
+	return &unionedSet
+}
+
+func (set *threadUnsafeSet) Intersect(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	intersection := newThreadUnsafeSet()
+	// 遍历较小的集合
+	if set.RetElementCount() < other.RetElementCount() {
+		for elem := range set.m {
+			if other.Contains(elem) {
+				intersection.Add(elem)
+			}
+		}
+	} else {
+		other.Each(func(elem interface{}) bool {
+			if set.Contains(elem) {
+				intersection.Add(elem)
+			}
+			return false
+		})
+	}
+	return &intersection
+}
+
+func (set *threadUnsafeSet) Difference(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	difference := newThreadUnsafeSet()
+	for elem := range set.m {
+		if !other.Contains(elem) {
+			difference.Add(elem)
+		}
+	}
+	return &difference
+}
+
+func (set *threadUnsafeSet) SymmetricDifference(other ReadOnlySet) MapSet {
+	other = orEmpty(other)
+
+	sd := newThreadUnsafeSet()
+	for elem := range set.m {
+		if !other.Contains(elem) {
+			sd.Add(elem)
+		}
+	}
+	other.Each(func(elem interface{}) bool {
+		if !set.Contains(elem) {
+			sd.Add(elem)
+		}
+		return false
+	})
+	return &sd
+}
+
+func (set *threadUnsafeSet) UnionWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		return 0
+	}
+
+	added := 0
+	other.Each(func(elem interface{}) bool {
+		if set.Add(elem) {
+			added++
+		}
+		return false
+	})
+	return added
+}
+
+func (set *threadUnsafeSet) IntersectWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		return 0
+	}
+
+	return set.RemoveIf(func(elem interface{}) bool {
+		return !other.Contains(elem)
+	})
+}
+
+func (set *threadUnsafeSet) DifferenceWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		removed := set.RetElementCount()
+		set.Clear()
+		return removed
+	}
+
+	return set.RemoveIf(func(elem interface{}) bool {
+		return other.Contains(elem)
+	})
+}
+
+func (set *threadUnsafeSet) SymmetricDifferenceWith(other ReadOnlySet) int {
+	other = orEmpty(other)
+	if other == ReadOnlySet(set) {
+		return set.DifferenceWith(other)
+	}
+
+	changed := 0
+	other.Each(func(elem interface{}) bool {
+		if !set.Delete(elem) {
+			set.Add(elem)
+		}
+		changed++
+		return false
+	})
+	return changed
+}
+
+func (set *threadUnsafeSet) IsSubset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if set.RetElementCount() > other.RetElementCount() {
+		return false
+	}
+	for elem := range set.m {
 		if !other.Contains(elem) {
 			return false
 		}
@@ -74,45 +297,153 @@ func (set *threadUnsafeSet) Equal(other MapSet) bool {
 	return true
 }
 
+func (set *threadUnsafeSet) IsProperSubset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	return set.RetElementCount() < other.RetElementCount() && set.IsSubset(other)
+}
+
+func (set *threadUnsafeSet) IsSuperset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if set.RetElementCount() < other.RetElementCount() {
+		return false
+	}
+	ret := true
+	other.Each(func(elem interface{}) bool {
+		if !set.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
+func (set *threadUnsafeSet) IsProperSuperset(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	return set.RetElementCount() > other.RetElementCount() && set.IsSuperset(other)
+}
+
+func (set *threadUnsafeSet) IsDisjoint(other ReadOnlySet) bool {
+	other = orEmpty(other)
+	if set.RetElementCount() == 0 || other.RetElementCount() == 0 {
+		return true
+	}
+	// 遍历较小的集合
+	if set.RetElementCount() <= other.RetElementCount() {
+		for elem := range set.m {
+			if other.Contains(elem) {
+				return false
+			}
+		}
+		return true
+	}
+	ret := true
+	other.Each(func(elem interface{}) bool {
+		if set.Contains(elem) {
+			ret = false
+			return true
+		}
+		return false
+	})
+	return ret
+}
+
 func (set *threadUnsafeSet) Clone() MapSet {
-	clonedSet := newThreadUnsafeSet()
-	for elem := range *set {
-		clonedSet.Add(elem)
+	clonedSet := threadUnsafeSet{
+		m:     make(map[interface{}]int, len(set.items)),
+		items: append([]interface{}(nil), set.items...),
+		rnd:   set.rnd,
+	}
+	for i, elem := range clonedSet.items {
+		clonedSet.m[elem] = i
 	}
 	return &clonedSet
 }
 
 
 func (set *threadUnsafeSet) String(sep string) string {
-	items := make([]string, 0, len(*set))
+	items := make([]string, 0, len(set.items))
 
-	for elem := range *set {
+	for elem := range set.m {
 		items = append(items, fmt.Sprintf("%v", elem))
 	}
 	return fmt.Sprintf("Set{%s}", strings.Join(items, sep))
 }
 
 func (set *threadUnsafeSet) RandomReturn() interface{} {
-	for item := range *set {
-		return item
+	if len(set.items) == 0 {
+		return nil
 	}
-	return nil
+	return set.items[set.rnd.intn(len(set.items))]
 }
 
-func (set *threadUnsafeSet) ToSlice() []interface{} {
-	keys := make([]interface{}, 0, set.RetElementCount())
-	for elem := range *set {
-		keys = append(keys, elem)
+func (set *threadUnsafeSet) Sample(k int) []interface{} {
+	return randomElems(set.rnd, set.items, k)
+}
+
+func (set *threadUnsafeSet) SampleWithReplacement(k int) []interface{} {
+	return randomElemsWithReplacement(set.rnd, set.items, k)
+}
+
+func (set *threadUnsafeSet) PopN(k int) []interface{} {
+	if k > len(set.items) {
+		k = len(set.items)
 	}
+	items := make([]interface{}, 0, max(k, 0))
+	for len(items) < k {
+		items = append(items, set.Pop())
+	}
+	return items
+}
+
+func (set *threadUnsafeSet) Shuffled() []interface{} {
+	return randomElems(set.rnd, set.items, len(set.items))
+}
 
+func (set *threadUnsafeSet) ToSlice() []interface{} {
+	keys := make([]interface{}, len(set.items))
+	copy(keys, set.items)
 	return keys
 }
 
+func (set *threadUnsafeSet) Freeze() FrozenSet {
+	return newFrozenSet(set.ToSlice())
+}
+
+func (set *threadUnsafeSet) Iterator() *Iterator {
+	return newIterator(set.ToSlice())
+}
+
+func (set *threadUnsafeSet) All() iter.Seq[interface{}] {
+	return snapshotSeq(set.ToSlice)
+}
+
 // MarshalJSON creates a JSON array from the set, it marshals all elements
 func (set *threadUnsafeSet) MarshalJSON() ([]byte, error) {
-	items := make([]string, 0, set.RetElementCount())
+	return marshalJSONElems(set.ToSlice())
+}
 
-	for elem := range *set {
+// UnmarshalJSON recreates a set from a JSON array, it only decodes
+// primitive types. Numbers are decoded as json.Number.
+// Use UnmarshalJSONWith for lossless, typed decoding.
+func (set *threadUnsafeSet) UnmarshalJSON(b []byte) error {
+	elems, err := unmarshalJSONPrimitives(b)
+	if err != nil {
+		return err
+	}
+
+	for _, elem := range elems {
+		set.Add(elem)
+	}
+
+	return nil
+}
+
+// marshalJSONElems把elems按给定顺序编码为JSON数组
+func marshalJSONElems(elems []interface{}) ([]byte, error) {
+	items := make([]string, 0, len(elems))
+
+	for _, elem := range elems {
 		b, err := json.Marshal(elem)
 		if err != nil {
 			return nil, err
@@ -124,26 +455,27 @@ func (set *threadUnsafeSet) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf("[%s]", strings.Join(items, ","))), nil
 }
 
-// UnmarshalJSON recreates a set from a JSON array, it only decodes
-// primitive types. Numbers are decoded as json.Number.
-func (set *threadUnsafeSet) UnmarshalJSON(b []byte) error {
+// unmarshalJSONPrimitives按数组顺序解码JSON数组中的基本类型元素,
+// 数组和对象元素会被跳过,数字解码为json.Number
+func unmarshalJSONPrimitives(b []byte) ([]interface{}, error) {
 	var i []interface{}
 
 	d := json.NewDecoder(bytes.NewReader(b))
 	d.UseNumber()
 	err := d.Decode(&i)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	elems := make([]interface{}, 0, len(i))
 	for _, v := range i {
 		switch t := v.(type) {
 		case []interface{}, map[string]interface{}:
 			continue
 		default:
-			set.Add(t)
+			elems = append(elems, t)
 		}
 	}
 
-	return nil
+	return elems, nil
 }